@@ -10,6 +10,7 @@ require (
 
 require (
 	github.com/SENERGY-Platform/device-repository v0.2.43
+	github.com/SENERGY-Platform/go-service-base/struct-logger v0.6.0
 	github.com/SENERGY-Platform/models/go v0.0.0-20260302084452-04ca9ee69c93
 	github.com/SENERGY-Platform/service-commons v0.0.0-20260423104942-3cd90b7ab170
 	github.com/prometheus/client_golang v1.19.1
@@ -21,7 +22,6 @@ require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
 	github.com/RyanCarrier/dijkstra v1.4.0 // indirect
 	github.com/SENERGY-Platform/developer-notifications v0.0.5 // indirect
-	github.com/SENERGY-Platform/go-service-base/struct-logger v0.6.0 // indirect
 	github.com/SENERGY-Platform/permissions-v2 v0.0.41 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
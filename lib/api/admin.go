@@ -0,0 +1,123 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/auth"
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, AdminEndpoints)
+}
+
+type ConceptRepoAdminInfo struct {
+	Status conceptrepo.Status `json:"status"`
+	Stats  conceptrepo.Stats  `json:"stats"`
+}
+
+func AdminEndpoints(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
+	resource := "/admin/concept-repo"
+
+	//the admin endpoints must not rely on a token verification by the api gateway
+	if config.AuthJwksUrl == "" || config.AuthJwksUrl == "-" {
+		config.GetLogger().Warn("admin endpoints are disabled because auth_jwks_url is not set", "endpoint", resource)
+		return
+	}
+	verifier := auth.NewVerifier(config.AuthJwksUrl)
+
+	respond := func(writer http.ResponseWriter, value interface{}) {
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err := json.NewEncoder(writer).Encode(value)
+		if err != nil {
+			config.GetLogger().Error("unable to encode response", "error", err)
+		}
+	}
+
+	admin := func(handler func(writer http.ResponseWriter, request *http.Request)) httprouter.Handle {
+		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+			token, err := verifier.Verify(request.Header.Get("Authorization"))
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			if !token.IsAdmin() {
+				http.Error(writer, "only admins may use this endpoint", http.StatusForbidden)
+				return
+			}
+			if conceptRepo == nil {
+				http.Error(writer, "concept-repo not available", http.StatusServiceUnavailable)
+				return
+			}
+			handler(writer, request)
+		}
+	}
+
+	info := func() ConceptRepoAdminInfo {
+		return ConceptRepoAdminInfo{Status: conceptRepo.Status(), Stats: conceptRepo.Stats()}
+	}
+
+	router.GET(resource, admin(func(writer http.ResponseWriter, request *http.Request) {
+		respond(writer, info())
+	}))
+
+	router.GET(resource+"/concepts", admin(func(writer http.ResponseWriter, request *http.Request) {
+		respond(writer, conceptRepo.Dump().Concepts)
+	}))
+
+	router.GET(resource+"/characteristics", admin(func(writer http.ResponseWriter, request *http.Request) {
+		respond(writer, conceptRepo.Dump().Characteristics)
+	}))
+
+	router.GET(resource+"/functions", admin(func(writer http.ResponseWriter, request *http.Request) {
+		dump := conceptRepo.Dump()
+		respond(writer, map[string]interface{}{
+			"function_to_concept":         dump.FunctionToConcept,
+			"characteristics_of_function": dump.CharacteristicsOfFunction,
+		})
+	}))
+
+	router.GET(resource+"/root-characteristics", admin(func(writer http.ResponseWriter, request *http.Request) {
+		respond(writer, conceptRepo.Dump().RootCharacteristicByCharacteristic)
+	}))
+
+	router.POST(resource+"/reload", admin(func(writer http.ResponseWriter, request *http.Request) {
+		err := conceptRepo.Load()
+		if err != nil {
+			config.GetLogger().Error("unable to reload concept-repo", "error", err)
+			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			writer.WriteHeader(http.StatusBadGateway)
+			err = json.NewEncoder(writer).Encode(info())
+			if err != nil {
+				config.GetLogger().Error("unable to encode response", "error", err)
+			}
+			return
+		}
+		respond(writer, info())
+	}))
+}
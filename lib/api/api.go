@@ -26,9 +26,11 @@ import (
 
 	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
 	"github.com/SENERGY-Platform/marshaller/lib/api/util"
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
@@ -41,20 +43,31 @@ type DeviceRepository interface {
 	GetProtocol(id string) (model.Protocol, error)
 	GetServiceWithErrCode(serviceId string) (model.Service, error, int)
 	GetAspectNode(id string) (model.AspectNode, error)
+	GetDeviceType(id string) (result model.DeviceType, err error, code int)
+	GetServiceWithToken(token string, serviceId string) (model.Service, error, int)
+	GetProtocolWithToken(token string, id string) (model.Protocol, error)
+	GetDeviceTypeWithToken(token string, id string) (model.DeviceType, error, int)
+	GetDevice(id string) (model.Device, error, int)
+	GetDeviceWithToken(token string, id string) (model.Device, error, int)
 }
 
-var endpoints = []func(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics){}
+type ConceptRepo interface {
+	Status() conceptrepo.Status
+	Stats() conceptrepo.Stats
+	Dump() conceptrepo.Dump
+	Load() error
+}
+
+var endpoints = []func(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo){}
 
-func Start(ctx context.Context, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter) (closed context.Context) {
+func Start(ctx context.Context, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, m *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) (closed context.Context) {
 	config.GetLogger().Info("start api")
-	m, err := metrics.Start(ctx, config)
-	if err != nil {
-		config.GetLogger().Warn("unable to serve metrics", "error", err)
-	}
-	router := GetRouter(config, marshaller, marshallerV2, configurableService, deviceRepo, converter, m)
-	config.GetLogger().Info("add logging and cors")
-	corsHandler := util.NewCors(router)
-	logger := accesslog.New(corsHandler)
+	router := NewRouter(config, marshaller, marshallerV2, configurableService, deviceRepo, converter, m, health, conceptRepo)
+	config.GetLogger().Info("add logging, metrics, auth and cors")
+	authHandler := util.NewAuth(config, router)
+	corsHandler := util.NewCors(config, authHandler)
+	metricsHandler := util.NewRequestMetrics(m, router, corsHandler)
+	logger := accesslog.New(metricsHandler)
 	config.GetLogger().Info("listen on port", "port", config.ServerPort)
 	srv := &http.Server{Addr: ":" + config.ServerPort, Handler: logger}
 	closed, close := context.WithCancel(context.Background())
@@ -67,7 +80,8 @@ func Start(ctx context.Context, config config.Config, marshaller *marshaller.Mar
 	}()
 	go func() {
 		<-ctx.Done()
-		timeout, _ := context.WithTimeout(context.Background(), 2*time.Second)
+		timeout, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
 		if err := srv.Shutdown(timeout); err != nil {
 			srv.Close()
 		}
@@ -75,11 +89,52 @@ func Start(ctx context.Context, config config.Config, marshaller *marshaller.Mar
 	return closed
 }
 
-func GetRouter(config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) (router *httprouter.Router) {
-	router = httprouter.New()
+// forCaller returns a DeviceRepository loading services, device-types and protocols with the callers token
+// if config.AuthForwardUserToken is set
+func forCaller(config config.Config, deviceRepo DeviceRepository, request *http.Request) DeviceRepository {
+	token := request.Header.Get("Authorization")
+	if !config.AuthForwardUserToken || token == "" {
+		return deviceRepo
+	}
+	return callerDeviceRepository{DeviceRepository: deviceRepo, token: token}
+}
+
+type callerDeviceRepository struct {
+	DeviceRepository
+	token string
+}
+
+func (this callerDeviceRepository) GetService(serviceId string) (result model.Service, err error) {
+	result, err, _ = this.GetServiceWithToken(this.token, serviceId)
+	return result, err
+}
+
+func (this callerDeviceRepository) GetServiceWithErrCode(serviceId string) (model.Service, error, int) {
+	return this.GetServiceWithToken(this.token, serviceId)
+}
+
+func (this callerDeviceRepository) GetProtocol(id string) (model.Protocol, error) {
+	return this.GetProtocolWithToken(this.token, id)
+}
+
+func (this callerDeviceRepository) GetDeviceType(id string) (model.DeviceType, error, int) {
+	return this.GetDeviceTypeWithToken(this.token, id)
+}
+
+func (this callerDeviceRepository) GetDevice(id string) (model.Device, error, int) {
+	return this.GetDeviceWithToken(this.token, id)
+}
+
+func GetRouter(config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) (router *httprouter.Router) {
+	return NewRouter(config, marshaller, marshallerV2, configurableService, deviceRepo, converter, metrics, health, conceptRepo).Router
+}
+
+// NewRouter is GetRouter with access to the registered routes
+func NewRouter(config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) (router *Router) {
+	router = newRouter()
 	for _, e := range endpoints {
 		config.GetLogger().Info("add endpoints", "endpoint", runtime.FuncForPC(reflect.ValueOf(e).Pointer()).Name())
-		e(router, config, marshaller, marshallerV2, configurableService, deviceRepo, converter, metrics)
+		e(router, config, marshaller, marshallerV2, configurableService, deviceRepo, converter, metrics, health, conceptRepo)
 	}
 	return
 }
@@ -22,17 +22,19 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
+	"strings"
 )
 
 func init() {
 	endpoints = append(endpoints, CharacteristicPathEndpoint)
 }
 
-func CharacteristicPathEndpoint(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) {
+func CharacteristicPathEndpoint(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
 	resource := "/characteristic-paths"
 
 	router.GET(resource+"/:serviceId/:characteristicId", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -46,11 +48,22 @@ func CharacteristicPathEndpoint(router *httprouter.Router, config config.Config,
 			http.Error(writer, "expect characteristicId as parameter in path", http.StatusBadRequest)
 			return
 		}
-		service, err, code := deviceRepo.GetServiceWithErrCode(serviceId)
+		service, err, code := forCaller(config, deviceRepo, request).GetServiceWithErrCode(serviceId)
 		if err != nil {
 			http.Error(writer, err.Error(), code)
 			return
 		}
+		//with direction all matches are returned; without the first matching output path (legacy response)
+		if direction, ok := request.URL.Query()["direction"]; ok {
+			result, err, code := marshaller.GetServiceCharacteristicPaths(service, characteristicId, strings.TrimSpace(direction[0]))
+			if err != nil {
+				http.Error(writer, err.Error(), code)
+				return
+			}
+			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			json.NewEncoder(writer).Encode(result)
+			return
+		}
 		result, err, code := marshaller.GetServiceCharacteristicPath(service, characteristicId)
 		if err != nil {
 			http.Error(writer, err.Error(), code)
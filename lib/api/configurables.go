@@ -26,6 +26,7 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
@@ -36,7 +37,7 @@ func init() {
 	endpoints = append(endpoints, Configurables)
 }
 
-func Configurables(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) {
+func Configurables(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
 	resource := "/configurables"
 
 	router.GET(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -53,7 +54,7 @@ func Configurables(router *httprouter.Router, config config.Config, marshaller *
 		serviceIds := strings.Split(serviceIdsStr, ",")
 		services := []model.Service{}
 		for _, id := range serviceIds {
-			service, err := deviceRepo.GetService(strings.TrimSpace(id))
+			service, err := forCaller(config, deviceRepo, request).GetService(strings.TrimSpace(id))
 			if err != nil {
 				http.Error(writer, err.Error(), http.StatusInternalServerError)
 				return
@@ -95,4 +96,60 @@ func Configurables(router *httprouter.Router, config config.Config, marshaller *
 		}
 	})
 
+	findForFunction := func(writer http.ResponseWriter, deviceRepo DeviceRepository, msg messages.FindFunctionConfigurablesRequest) {
+		if msg.FunctionId == "" {
+			http.Error(writer, "expect function_id", http.StatusBadRequest)
+			return
+		}
+		if msg.AspectNode == nil && msg.AspectNodeId != "" {
+			aspectNode, err := deviceRepo.GetAspectNode(msg.AspectNodeId)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			msg.AspectNode = &aspectNode
+		}
+		for _, id := range msg.DeviceTypeIds {
+			deviceType, err, code := deviceRepo.GetDeviceType(strings.TrimSpace(id))
+			if err != nil {
+				http.Error(writer, err.Error(), code)
+				return
+			}
+			msg.DeviceTypes = append(msg.DeviceTypes, deviceType)
+		}
+		result, err := configurableService.FindForFunction(msg.FunctionId, msg.AspectNode, msg.DeviceTypes)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			config.GetLogger().Error("unable to encode response", "error", err)
+		}
+	}
+
+	router.GET("/v2"+resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		msg := messages.FindFunctionConfigurablesRequest{
+			FunctionId:   request.URL.Query().Get("function_id"),
+			AspectNodeId: request.URL.Query().Get("aspect_id"),
+		}
+		deviceTypeIds := request.URL.Query().Get("device_type_ids")
+		if deviceTypeIds == "" {
+			http.Error(writer, "expect device_type_ids as query-parameter", http.StatusBadRequest)
+			return
+		}
+		msg.DeviceTypeIds = strings.Split(deviceTypeIds, ",")
+		findForFunction(writer, forCaller(config, deviceRepo, request), msg)
+	})
+
+	router.POST("/v2"+resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		msg := messages.FindFunctionConfigurablesRequest{}
+		err := json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		findForFunction(writer, forCaller(config, deviceRepo, request), msg)
+	})
 }
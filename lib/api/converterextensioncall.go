@@ -24,6 +24,7 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
 	"github.com/julienschmidt/httprouter"
@@ -33,7 +34,7 @@ func init() {
 	endpoints = append(endpoints, ConversionExtensionEndpoints)
 }
 
-func ConversionExtensionEndpoints(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, c *converter.Converter, metrics *metrics.Metrics) {
+func ConversionExtensionEndpoints(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, c *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
 	resource := "/converter/extension-call"
 
 	router.POST(resource, func(writer http.ResponseWriter, request *http.Request, ps httprouter.Params) {
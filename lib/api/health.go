@@ -0,0 +1,57 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, HealthEndpoints)
+}
+
+func HealthEndpoints(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
+	router.GET("/health/live", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err := json.NewEncoder(writer).Encode(map[string]bool{"live": true})
+		if err != nil {
+			config.GetLogger().Error("unable to encode response", "error", err)
+		}
+	})
+
+	router.GET("/health/ready", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		report := health.Ready(request.Context())
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if !report.Ready {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}
+		err := json.NewEncoder(writer).Encode(report)
+		if err != nil {
+			config.GetLogger().Error("unable to encode response", "error", err)
+		}
+	})
+}
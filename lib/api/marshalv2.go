@@ -27,8 +27,10 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -36,10 +38,10 @@ func init() {
 	endpoints = append(endpoints, MarshallingV2)
 }
 
-func MarshallingV2(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) {
+func MarshallingV2(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
 	resource := "/v2/marshal"
 
-	normalizeRequest := func(request *messages.MarshallingV2Request) error {
+	normalizeRequest := func(deviceRepo DeviceRepository, request *messages.MarshallingV2Request) error {
 		if request.Protocol.Id == "" {
 			protocol, err := deviceRepo.GetProtocol(request.Service.ProtocolId)
 			if err != nil {
@@ -54,7 +56,34 @@ func MarshallingV2(router *httprouter.Router, config config.Config, marshaller *
 	}
 
 	marshal := func(request messages.MarshallingV2Request) (map[string]string, error) {
-		return marshallerV2.Marshal(request.Protocol, request.Service, request.Data)
+		validate := config.ValidateMarshallingInput
+		if request.Validate != nil {
+			validate = *request.Validate
+		}
+		return marshallerV2.MarshalWithOptions(request.Protocol, request.Service, request.Data, v2.MarshalOptions{
+			Configurables:    request.Configurables,
+			Validate:         validate,
+			SegmentTemplates: request.SegmentTemplates,
+			DeviceId:         request.DeviceId,
+			DeviceLocalId:    request.DeviceLocalId,
+		})
+	}
+
+	handleMarshalError := func(writer http.ResponseWriter, err error) {
+		var validationErr *validation.Error
+		if errors.As(err, &validationErr) {
+			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			writer.WriteHeader(http.StatusBadRequest)
+			err = json.NewEncoder(writer).Encode(messages.ValidationErrorResponse{
+				Error:      validationErr.Error(),
+				Violations: validationErr.Violations,
+			})
+			if err != nil {
+				config.GetLogger().Error("unable to encode response", "error", err)
+			}
+			return
+		}
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
 	}
 
 	router.POST(resource+"/:serviceId", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -70,19 +99,19 @@ func MarshallingV2(router *httprouter.Router, config config.Config, marshaller *
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		msg.Service, err = deviceRepo.GetService(serviceId)
+		msg.Service, err = forCaller(config, deviceRepo, request).GetService(serviceId)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		err = normalizeRequest(&msg)
+		err = normalizeRequest(forCaller(config, deviceRepo, request), &msg)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 		result, err := marshal(msg)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			handleMarshalError(writer, err)
 			return
 		}
 
@@ -102,14 +131,14 @@ func MarshallingV2(router *httprouter.Router, config config.Config, marshaller *
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		err = normalizeRequest(&msg)
+		err = normalizeRequest(forCaller(config, deviceRepo, request), &msg)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 		result, err := marshal(msg)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			handleMarshalError(writer, err)
 			return
 		}
 		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
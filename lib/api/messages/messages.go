@@ -19,6 +19,7 @@ package messages
 import (
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
 )
 
 type MarshallingRequest struct {
@@ -40,6 +41,18 @@ type MarshallingV2Request struct {
 	Service  model.Service                    `json:"service"`  //semi-optional, may be determined by request path
 	Protocol model.Protocol                   `json:"protocol"` //semi-optional, may be determined by service
 	Data     []model.MarshallingV2RequestData `json:"data"`
+
+	Configurables []configurables.Configurable `json:"configurables,omitempty"` //optional, applied to all input paths with a matching concept that are not set by Data
+	Validate      *bool                        `json:"validate,omitempty"`      //optional, defaults to config.ValidateMarshallingInput
+
+	SegmentTemplates map[string]string `json:"segment_templates,omitempty"` //optional, protocol segment name to text/template; overrides service attribute templates
+	DeviceId         string            `json:"device_id,omitempty"`         //optional, available in segment templates
+	DeviceLocalId    string            `json:"device_local_id,omitempty"`   //optional, available in segment templates
+}
+
+type ValidationErrorResponse struct {
+	Error      string                 `json:"error"`
+	Violations []validation.Violation `json:"violations"`
 }
 
 type UnmarshallingRequest struct {
@@ -69,6 +82,8 @@ type UnmarshallingV2Request struct {
 	FunctionId   string           `json:"function_id"`    //semi-optional, to determine Path if not set
 	AspectNode   model.AspectNode `json:"aspect_node"`    //semi-optional, to determine Path if not set, may itself be determent by AspectNodeId
 	AspectNodeId string           `json:"aspect_node_id"` //semi-optional, to determine AspectNode if not set
+
+	WholeList bool `json:"whole_list,omitempty"` //optional, returns every element of the variable length lists marked by '*' in Path as list of v2.ListElement
 }
 
 type FindConfigurablesRequest struct {
@@ -76,10 +91,29 @@ type FindConfigurablesRequest struct {
 	Services         []model.Service `json:"services"`
 }
 
+type FindFunctionConfigurablesRequest struct {
+	FunctionId    string             `json:"function_id"`
+	AspectNode    *model.AspectNode  `json:"aspect_node,omitempty"`    //optional
+	AspectNodeId  string             `json:"aspect_node_id,omitempty"` //optional, to determine AspectNode if not set
+	DeviceTypes   []model.DeviceType `json:"device_types,omitempty"`   //semi-optional, may be determined by DeviceTypeIds
+	DeviceTypeIds []string           `json:"device_type_ids,omitempty"`
+}
+
 type PathOptionsQuery struct {
 	DeviceTypeIds          []string `json:"device_type_ids"`
+	DeviceIds              []string `json:"device_ids,omitempty"` //results are indexed by device id
 	FunctionId             string   `json:"function_id"`
 	AspectId               string   `json:"aspect_id"`
 	CharacteristicIdFilter []string `json:"characteristic_id_filter"`
 	WithoutEnvelope        bool     `json:"without_envelope"`
+	Direction              string   `json:"direction,omitempty"` //"outputs" (default) or "inputs"
+}
+
+type PathOptionsV2Query struct {
+	DeviceTypeIds          []string `json:"device_type_ids"`
+	DeviceIds              []string `json:"device_ids,omitempty"`               //results are indexed by device id
+	FunctionId             string   `json:"function_id"`                        //optional
+	AspectId               string   `json:"aspect_id"`                          //optional
+	CharacteristicIdFilter []string `json:"characteristic_id_filter,omitempty"` //optional
+	Direction              string   `json:"direction,omitempty"`                //"outputs" (default) or "inputs"
 }
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"container/list"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+const DefaultCallSourceCacheSize = 1000
+const DefaultCallSourceCacheExpiration = time.Hour
+
+// leading ips of host names (e.g. 10-42-17-133.pessimistic-worker-metrics.process-task-worker.svc.cluster.local.)
+var leadingIpPattern = regexp.MustCompile(`^(\d{3}|\d{2}|\d{1})-(\d{3}|\d{2}|\d{1})-(\d{3}|\d{2}|\d{1})-(\d{3}|\d{2}|\d{1})\.`)
+
+// callSourceCache maps remote hosts to call sources; least recently used entries are removed if size is exceeded
+type callSourceCache struct {
+	mux        sync.Mutex
+	size       int
+	expiration time.Duration
+	entries    map[string]*list.Element
+	order      *list.List //front is most recently used
+	lookupAddr func(addr string) ([]string, error)
+}
+
+type callSourceCacheEntry struct {
+	host    string
+	source  string
+	expires time.Time
+}
+
+func newCallSourceCache(size int, expiration time.Duration) *callSourceCache {
+	if size <= 0 {
+		size = DefaultCallSourceCacheSize
+	}
+	if expiration <= 0 {
+		expiration = DefaultCallSourceCacheExpiration
+	}
+	return &callSourceCache{
+		size:       size,
+		expiration: expiration,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+		lookupAddr: net.LookupAddr,
+	}
+}
+
+// Get returns the call source of the request; the port of the remote address is ignored
+func (this *callSourceCache) Get(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil || host == "" {
+		host = req.RemoteAddr
+	}
+	if source, ok := this.get(host); ok {
+		return source
+	}
+	source := this.lookup(host)
+	this.set(host, source)
+	return source
+}
+
+func (this *callSourceCache) lookup(host string) string {
+	remoteHosts, _ := this.lookupAddr(host)
+	if len(remoteHosts) == 0 {
+		return host
+	}
+	sort.Strings(remoteHosts)
+	return leadingIpPattern.ReplaceAllString(remoteHosts[0], "")
+}
+
+func (this *callSourceCache) get(host string) (source string, ok bool) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	element, ok := this.entries[host]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*callSourceCacheEntry)
+	if config.TimeNow().After(entry.expires) {
+		this.order.Remove(element)
+		delete(this.entries, host)
+		return "", false
+	}
+	this.order.MoveToFront(element)
+	return entry.source, true
+}
+
+func (this *callSourceCache) set(host string, source string) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	entry := &callSourceCacheEntry{host: host, source: source, expires: config.TimeNow().Add(this.expiration)}
+	if element, ok := this.entries[host]; ok {
+		element.Value = entry
+		this.order.MoveToFront(element)
+		return
+	}
+	this.entries[host] = this.order.PushFront(entry)
+	for this.order.Len() > this.size {
+		oldest := this.order.Back()
+		this.order.Remove(oldest)
+		delete(this.entries, oldest.Value.(*callSourceCacheEntry).host)
+	}
+}
+
+func (this *callSourceCache) Len() int {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.order.Len()
+}
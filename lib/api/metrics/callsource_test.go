@@ -0,0 +1,76 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+func TestCallSourceCache(t *testing.T) {
+	now := time.Now()
+	config.TimeNow = func() time.Time { return now }
+	defer func() { config.TimeNow = time.Now }()
+
+	lookups := map[string]int{}
+	cache := newCallSourceCache(2, time.Minute)
+	cache.lookupAddr = func(addr string) ([]string, error) {
+		lookups[addr]++
+		if addr == "10.0.0.1" {
+			return []string{"10-0-0-1.worker.svc.cluster.local.", "b.example.com."}, nil
+		}
+		return nil, nil
+	}
+	get := func(remoteAddr string) string {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = remoteAddr
+		return cache.Get(req)
+	}
+
+	if source := get("10.0.0.1:1234"); source != "worker.svc.cluster.local." {
+		t.Error(source)
+	}
+	if source := get("10.0.0.1:4321"); source != "worker.svc.cluster.local." {
+		t.Error(source)
+	}
+	if source := get("10.0.0.2:1234"); source != "10.0.0.2" {
+		t.Error(source)
+	}
+	get("10.0.0.2:1235")
+	if lookups["10.0.0.1"] != 1 || lookups["10.0.0.2"] != 1 {
+		t.Error(lookups)
+	}
+
+	get("10.0.0.3:1234")
+	if cache.Len() != 2 {
+		t.Error(cache.Len())
+	}
+	get("10.0.0.2:1234")
+	get("10.0.0.1:1234")
+	if lookups["10.0.0.1"] != 2 || lookups["10.0.0.2"] != 1 {
+		t.Error("expected least recently used entry to be removed", lookups)
+	}
+
+	now = now.Add(2 * time.Minute)
+	get("10.0.0.2:1234")
+	if lookups["10.0.0.2"] != 2 {
+		t.Error("expected expired entry to be looked up again", lookups)
+	}
+}
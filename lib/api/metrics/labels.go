@@ -0,0 +1,99 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OtherLabelValue replaces label values that are not allowed or exceed the configured max count of distinct values
+const OtherLabelValue = "other"
+
+const (
+	LabelCallSource  = "call_source"
+	LabelEndpoint    = "endpoint"
+	LabelServiceId   = "service_id"
+	LabelFunctionIds = "function_ids"
+)
+
+// RequestLabels are the labels of the (un)marshalling request histograms if none are configured
+var RequestLabels = []string{LabelCallSource, LabelEndpoint, LabelServiceId, LabelFunctionIds}
+
+// labelValues bounds the distinct values of a label by an optional allow-list and an optional max count
+type labelValues struct {
+	mux     sync.Mutex
+	allowed map[string]bool //nil allows all values
+	max     int             //0 is unlimited
+	known   map[string]bool
+}
+
+func newLabelValues(allowList []string, max int64) *labelValues {
+	result := &labelValues{max: int(max), known: map[string]bool{}}
+	if len(allowList) > 0 {
+		result.allowed = map[string]bool{}
+		for _, value := range allowList {
+			result.allowed[value] = true
+		}
+	}
+	return result
+}
+
+func (this *labelValues) isAllowed(value string) bool {
+	return this.allowed == nil || this.allowed[value]
+}
+
+// Get returns value if it is allowed and one of the first max distinct values, else OtherLabelValue
+func (this *labelValues) Get(value string) string {
+	if !this.isAllowed(value) {
+		return OtherLabelValue
+	}
+	return this.limit(value)
+}
+
+// GetList filters each element by the allow-list and limits the joined result
+func (this *labelValues) GetList(values []string) string {
+	filtered := []string{}
+	for _, value := range values {
+		if !this.isAllowed(value) {
+			value = OtherLabelValue
+		}
+		if !slices.Contains(filtered, value) {
+			filtered = append(filtered, value)
+		}
+	}
+	sort.Strings(filtered)
+	return this.limit(strings.Join(filtered, ","))
+}
+
+func (this *labelValues) limit(value string) string {
+	if this.max <= 0 || value == OtherLabelValue {
+		return value
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.known[value] {
+		return value
+	}
+	if len(this.known) >= this.max {
+		return OtherLabelValue
+	}
+	this.known[value] = true
+	return value
+}
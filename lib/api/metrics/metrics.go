@@ -17,16 +17,16 @@
 package metrics
 
 import (
-	"net"
 	"net/http"
-	"regexp"
-	"sort"
-	"strings"
-	"sync"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
 	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/devicerepository"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -34,9 +34,26 @@ import (
 func NewMetrics(conf config.Config) *Metrics {
 	reg := prometheus.NewRegistry()
 
+	requestLabels := []string{}
+	for _, label := range RequestLabels {
+		if len(conf.MetricsLabels) == 0 || slices.Contains(conf.MetricsLabels, label) {
+			requestLabels = append(requestLabels, label)
+		}
+	}
+	for _, label := range conf.MetricsLabels {
+		if !slices.Contains(RequestLabels, label) {
+			conf.GetLogger().Warn("ignore unknown metrics label", "label", label)
+		}
+	}
+
 	result := &Metrics{
-		config:             conf,
-		getCallSourceCache: map[string]string{},
+		config:           conf,
+		registry:         reg,
+		requestLabels:    requestLabels,
+		callSources:      newCallSourceCache(int(conf.MetricsCallSourceCacheSize), time.Duration(conf.MetricsCallSourceCacheExpiration)*time.Second),
+		callSourceLabels: newLabelValues(nil, conf.MetricsMaxLabelValues),
+		serviceIdLabels:  newLabelValues(conf.MetricsServiceIdAllowList, conf.MetricsMaxLabelValues),
+		functionIdLabels: newLabelValues(conf.MetricsFunctionIdAllowList, conf.MetricsMaxLabelValues),
 		httphandler: promhttp.HandlerFor(
 			reg,
 			promhttp.HandlerOpts{
@@ -51,7 +68,7 @@ func NewMetrics(conf config.Config) *Metrics {
 			Name:    "marshaller_marshalling_request_handling_duration",
 			Help:    "histogram vec for handling duration (in μs) of marshalling request",
 			Buckets: []float64{500, 600, 700, 800, 900, 1000, 2000, 3000, 4000, 5000, 10000, 50000, 100000, 1000000},
-		}, []string{"call_source", "endpoint", "service_id", "function_ids"}),
+		}, requestLabels),
 
 		UnmarshallingRequestsSummary: prometheus.NewSummary(prometheus.SummaryOpts{
 			Name: "marshaller_unmarshalling_request_handling_duration_summary",
@@ -61,7 +78,23 @@ func NewMetrics(conf config.Config) *Metrics {
 			Name:    "marshaller_unmarshalling_request_handling_duration",
 			Help:    "histogram vec for handling duration (in μs) of unmarshalling request",
 			Buckets: []float64{500, 600, 700, 800, 900, 1000, 2000, 3000, 4000, 5000, 10000, 50000, 100000, 1000000},
-		}, []string{"call_source", "endpoint", "service_id", "function_ids"}),
+		}, requestLabels),
+
+		OutputViolations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "marshaller_unmarshalling_output_violations_total",
+			Help: "count of invalid output values found by the output validation",
+		}, []string{"service_id", "reason", "policy"}),
+
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "marshaller_requests_total",
+			Help: "count of api requests by route, status (success, client_error or server_error) and http status code",
+		}, []string{"endpoint", "method", "status", "code"}),
+
+		ConverterCalls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "marshaller_converter_call_duration_seconds",
+			Help:    "histogram vec for the duration of converter calls by characteristic pair",
+			Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"from", "to", "status"}),
 	}
 
 	reg.MustRegister(
@@ -70,22 +103,136 @@ func NewMetrics(conf config.Config) *Metrics {
 
 		result.UnmarshallingRequestsSummary,
 		result.UnmarshallingRequests,
+
+		result.OutputViolations,
+
+		result.Requests,
+		result.ConverterCalls,
 	)
 
 	return result
 }
 
 type Metrics struct {
-	httphandler           http.Handler
-	getCallSourceCache    map[string]string
-	getCallSourceCacheMux sync.Mutex
+	httphandler      http.Handler
+	registry         *prometheus.Registry
+	requestLabels    []string
+	callSources      *callSourceCache
+	callSourceLabels *labelValues
+	serviceIdLabels  *labelValues
+	functionIdLabels *labelValues
 
 	MarshallingRequestsSummary prometheus.Summary
 	MarshallingRequests        *prometheus.HistogramVec
 
 	UnmarshallingRequestsSummary prometheus.Summary
 	UnmarshallingRequests        *prometheus.HistogramVec
-	config                       config.Config
+
+	OutputViolations *prometheus.CounterVec
+
+	Requests       *prometheus.CounterVec
+	ConverterCalls *prometheus.HistogramVec
+
+	config config.Config
+}
+
+type ConceptRepo interface {
+	SnapshotAge() time.Duration
+	Stats() conceptrepo.Stats
+	Sizes() conceptrepo.Sizes
+}
+
+type DeviceRepository interface {
+	CacheStats() map[string]devicerepository.CacheStats
+}
+
+func (this *Metrics) ObserveConceptRepo(repo ConceptRepo) {
+	if this == nil {
+		return
+	}
+	this.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "marshaller_concept_repo_snapshot_age_seconds",
+		Help: "seconds since the concept-repo state in use was loaded from the device-repository",
+	}, func() float64 {
+		return repo.SnapshotAge().Seconds()
+	}))
+	this.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "marshaller_concept_repo_last_full_load_duration_seconds",
+		Help: "duration of the last full load of the concept-repo",
+	}, func() float64 {
+		return repo.Stats().LastFullLoadSeconds
+	}))
+	this.registry.MustRegister(&conceptRepoSizeCollector{
+		repo: repo,
+		desc: prometheus.NewDesc(
+			"marshaller_concept_repo_size",
+			"count of concepts, characteristics (including sub characteristics) and functions in the concept-repo",
+			[]string{"kind"}, nil,
+		),
+	})
+}
+
+// conceptRepoSizeCollector reads the sizes of the concept-repo once per scrape
+type conceptRepoSizeCollector struct {
+	repo ConceptRepo
+	desc *prometheus.Desc
+}
+
+func (this *conceptRepoSizeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- this.desc
+}
+
+func (this *conceptRepoSizeCollector) Collect(ch chan<- prometheus.Metric) {
+	sizes := this.repo.Sizes()
+	ch <- prometheus.MustNewConstMetric(this.desc, prometheus.GaugeValue, float64(sizes.Concepts), "concepts")
+	ch <- prometheus.MustNewConstMetric(this.desc, prometheus.GaugeValue, float64(sizes.Characteristics), "characteristics")
+	ch <- prometheus.MustNewConstMetric(this.desc, prometheus.GaugeValue, float64(sizes.Functions), "functions")
+}
+
+func (this *Metrics) ObserveDeviceRepository(repo DeviceRepository) {
+	if this == nil {
+		return
+	}
+	for _, kind := range devicerepository.CacheKinds {
+		for result, count := range map[string]func(stats devicerepository.CacheStats) int64{
+			"hit":  func(stats devicerepository.CacheStats) int64 { return stats.Hits },
+			"miss": func(stats devicerepository.CacheStats) int64 { return stats.Misses },
+		} {
+			this.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Name:        "marshaller_device_repository_cache_requests_total",
+				Help:        "count of device-repository cache lookups by kind and result (hit or miss)",
+				ConstLabels: prometheus.Labels{"kind": kind, "result": result},
+			}, func() float64 {
+				return float64(count(repo.CacheStats()[kind]))
+			}))
+		}
+	}
+}
+
+// LogRequest counts a handled api request; endpoint is the route pattern (e.g. '/v2/marshal/:serviceId')
+func (this *Metrics) LogRequest(endpoint string, method string, code int) {
+	if this == nil {
+		return
+	}
+	status := "success"
+	switch {
+	case code >= 500:
+		status = "server_error"
+	case code >= 400:
+		status = "client_error"
+	}
+	this.Requests.WithLabelValues(endpoint, method, status, strconv.Itoa(code)).Inc()
+}
+
+func (this *Metrics) LogConverterCall(from string, to string, duration time.Duration, err error) {
+	if this == nil {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	this.ConverterCalls.WithLabelValues(from, to, status).Observe(duration.Seconds())
 }
 
 func (this *Metrics) LogMarshallingRequest(request *http.Request, endpoint string, msg messages.MarshallingV2Request, duration time.Duration) {
@@ -100,9 +247,8 @@ func (this *Metrics) LogMarshallingRequest(request *http.Request, endpoint strin
 	for _, data := range msg.Data {
 		functionIds = append(functionIds, data.FunctionId)
 	}
-	sort.Strings(functionIds)
 
-	this.MarshallingRequests.WithLabelValues(this.getCallSource(request), endpoint, msg.Service.Id, strings.Join(functionIds, ",")).Observe(dur)
+	this.MarshallingRequests.With(this.getRequestLabels(request, endpoint, msg.Service.Id, functionIds)).Observe(dur)
 }
 
 func (this *Metrics) LogUnmarshallingRequest(request *http.Request, endpoint string, msg messages.UnmarshallingV2Request, duration time.Duration) {
@@ -111,34 +257,32 @@ func (this *Metrics) LogUnmarshallingRequest(request *http.Request, endpoint str
 	}
 	dur := float64(duration.Microseconds())
 	this.UnmarshallingRequestsSummary.Observe(dur)
-	this.UnmarshallingRequests.WithLabelValues(this.getCallSource(request), endpoint, msg.Service.Id, msg.FunctionId).Observe(dur)
+	this.UnmarshallingRequests.With(this.getRequestLabels(request, endpoint, msg.Service.Id, []string{msg.FunctionId})).Observe(dur)
 }
 
-func (this *Metrics) getCallSource(req *http.Request) (result string) {
-	this.getCallSourceCacheMux.Lock()
-	var cacheHit bool
-	result, cacheHit = this.getCallSourceCache[req.RemoteAddr]
-	this.getCallSourceCacheMux.Unlock()
-	if cacheHit {
-		return result
-	}
-
-	result = req.RemoteAddr
-	remoteAddr, _, err := net.SplitHostPort(result)
-	if err == nil && remoteAddr != "" {
-		remoteHosts, _ := net.LookupAddr(remoteAddr)
-		if len(remoteHosts) > 0 {
-			sort.Strings(remoteHosts)
-			result = remoteHosts[0]
-
-			//remove leading ips if exist (e.g. 10-42-17-133.pessimistic-worker-metrics.process-task-worker.svc.cluster.local.)
-			result = regexp.MustCompile(`^(\d{3}|\d{2}|\d{1})-(\d{3}|\d{2}|\d{1})-(\d{3}|\d{2}|\d{1})-(\d{3}|\d{2}|\d{1})\.`).ReplaceAllString(result, "")
-
-			this.getCallSourceCacheMux.Lock()
-			this.getCallSourceCache[req.RemoteAddr] = result
-			this.getCallSourceCacheMux.Unlock()
-		}
+func (this *Metrics) LogOutputViolations(serviceId string, policy string, violations []validation.Violation) {
+	if this == nil {
+		return
+	}
+	for _, violation := range violations {
+		this.OutputViolations.WithLabelValues(this.serviceIdLabels.Get(serviceId), violation.Reason, policy).Inc()
 	}
+}
 
+// getRequestLabels returns the configured labels; call sources, service ids and function ids are bounded by allow-lists and MetricsMaxLabelValues
+func (this *Metrics) getRequestLabels(request *http.Request, endpoint string, serviceId string, functionIds []string) prometheus.Labels {
+	result := prometheus.Labels{}
+	for _, label := range this.requestLabels {
+		switch label {
+		case LabelCallSource:
+			result[label] = this.callSourceLabels.Get(this.callSources.Get(request))
+		case LabelEndpoint:
+			result[label] = endpoint
+		case LabelServiceId:
+			result[label] = this.serviceIdLabels.Get(serviceId)
+		case LabelFunctionIds:
+			result[label] = this.functionIdLabels.GetList(functionIds)
+		}
+	}
 	return result
 }
@@ -0,0 +1,360 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, OpenApiEndpoint)
+}
+
+// route describes a registered endpoint in the openapi document.
+// Request and Response are example values of the json body types; nil for no body, oneOf for alternative types.
+type route struct {
+	Method      string
+	Path        string //httprouter syntax
+	Tag         string
+	Summary     string
+	Query       []queryParam
+	Request     interface{}
+	Response    interface{}
+	ErrorBodies map[int]interface{} //json error responses by status code; other errors are text/plain
+}
+
+type queryParam struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+type oneOf []interface{}
+
+var anyValue = new(interface{})
+
+// routes must list every endpoint registered by the endpoints slice; lib/tests/openapi_test.go checks this
+var routes = []route{
+	{Method: http.MethodPost, Path: "/marshal", Tag: "marshal", Summary: "marshal data of a characteristic to the protocol segments of a service",
+		Request: messages.MarshallingRequest{}, Response: map[string]string{}},
+	{Method: http.MethodPost, Path: "/marshal/:serviceId/:characteristicId", Tag: "marshal", Summary: "marshal data of a characteristic to the protocol segments of a stored service",
+		Request: messages.MarshallingRequest{}, Response: map[string]string{}},
+	{Method: http.MethodPost, Path: "/v2/marshal", Tag: "marshal", Summary: "marshal data to the protocol segments of a service",
+		Request: messages.MarshallingV2Request{}, Response: map[string]string{}, ErrorBodies: map[int]interface{}{http.StatusBadRequest: messages.ValidationErrorResponse{}}},
+	{Method: http.MethodPost, Path: "/v2/marshal/:serviceId", Tag: "marshal", Summary: "marshal data to the protocol segments of a stored service",
+		Request: messages.MarshallingV2Request{}, Response: map[string]string{}, ErrorBodies: map[int]interface{}{http.StatusBadRequest: messages.ValidationErrorResponse{}}},
+
+	{Method: http.MethodPost, Path: "/unmarshal", Tag: "unmarshal", Summary: "unmarshal a protocol message of a service to a characteristic",
+		Request: messages.UnmarshallingRequest{}, Response: anyValue},
+	{Method: http.MethodPost, Path: "/unmarshal/:serviceId/:characteristicId", Tag: "unmarshal", Summary: "unmarshal a protocol message of a stored service to a characteristic",
+		Request: messages.UnmarshallingRequest{}, Response: anyValue},
+	{Method: http.MethodPost, Path: "/v2/unmarshal", Tag: "unmarshal", Summary: "unmarshal the value at a path of a protocol message; violations of the output validation are listed in the " + OutputViolationsHeader + " header",
+		Request: messages.UnmarshallingV2Request{}, Response: oneOf{anyValue, []v2.ListElement{}}, ErrorBodies: map[int]interface{}{http.StatusUnprocessableEntity: messages.ValidationErrorResponse{}}},
+	{Method: http.MethodPost, Path: "/v2/unmarshal/:serviceId", Tag: "unmarshal", Summary: "unmarshal the value at a path of a protocol message of a stored service; violations of the output validation are listed in the " + OutputViolationsHeader + " header",
+		Request: messages.UnmarshallingV2Request{}, Response: oneOf{anyValue, []v2.ListElement{}}, ErrorBodies: map[int]interface{}{http.StatusUnprocessableEntity: messages.ValidationErrorResponse{}}},
+
+	{Method: http.MethodGet, Path: "/configurables", Tag: "configurables", Summary: "find configurables of services",
+		Query: []queryParam{
+			{Name: "characteristicId", Description: "characteristic that is not configurable", Required: true},
+			{Name: "serviceIds", Description: "comma separated service ids", Required: true},
+		}, Response: configurables.Configurables{}},
+	{Method: http.MethodPost, Path: "/configurables", Tag: "configurables", Summary: "find configurables of services",
+		Request: messages.FindConfigurablesRequest{}, Response: configurables.Configurables{}},
+	{Method: http.MethodGet, Path: "/v2/configurables", Tag: "configurables", Summary: "find configurables of device-types for a function",
+		Query: []queryParam{
+			{Name: "function_id", Required: true},
+			{Name: "aspect_id"},
+			{Name: "device_type_ids", Description: "comma separated device-type ids", Required: true},
+		}, Response: []configurables.DeviceTypeConfigurables{}},
+	{Method: http.MethodPost, Path: "/v2/configurables", Tag: "configurables", Summary: "find configurables of device-types for a function",
+		Request: messages.FindFunctionConfigurablesRequest{}, Response: []configurables.DeviceTypeConfigurables{}},
+
+	{Method: http.MethodGet, Path: "/path-options", Tag: "path-options", Summary: "list paths of device-types or devices with characteristics of a function; indexed by device-type or device id",
+		Query: []queryParam{
+			{Name: "device-type-ids", Description: "comma separated device-type ids"},
+			{Name: "device-ids", Description: "comma separated device ids"},
+			{Name: "characteristic-filter", Description: "comma separated characteristic ids", Required: true},
+			{Name: "function-id"},
+			{Name: "aspect-id"},
+			{Name: "direction", Description: model.DirectionOutputs + " (default) or " + model.DirectionInputs},
+		}, Response: map[string][]marshaller.PathOptionsResultElement{}},
+	{Method: http.MethodPost, Path: "/query/path-options", Tag: "path-options", Summary: "list paths of device-types or devices with characteristics of a function; indexed by device-type or device id",
+		Request: messages.PathOptionsQuery{}, Response: map[string][]marshaller.PathOptionsResultElement{}},
+	{Method: http.MethodGet, Path: "/v2/path-options", Tag: "path-options", Summary: "list paths of device-types or devices by function and aspect; indexed by device-type or device id",
+		Query: []queryParam{
+			{Name: "device-type-ids", Description: "comma separated device-type ids"},
+			{Name: "device-ids", Description: "comma separated device ids"},
+			{Name: "function-id"},
+			{Name: "aspect-id"},
+			{Name: "characteristic-filter", Description: "comma separated characteristic ids"},
+			{Name: "direction", Description: model.DirectionOutputs + " (default) or " + model.DirectionInputs},
+		}, Response: map[string][]v2.PathOption{}},
+	{Method: http.MethodPost, Path: "/v2/query/path-options", Tag: "path-options", Summary: "list paths of device-types or devices by function and aspect; indexed by device-type or device id",
+		Request: messages.PathOptionsV2Query{}, Response: map[string][]v2.PathOption{}},
+
+	{Method: http.MethodGet, Path: "/characteristic-paths/:serviceId/:characteristicId", Tag: "characteristic-paths", Summary: "find the output path of a characteristic in a service; with direction all matching paths of outputs or inputs are listed",
+		Query: []queryParam{
+			{Name: "direction", Description: model.DirectionOutputs + " or " + model.DirectionInputs},
+		}, Response: oneOf{marshaller.CharacteristicsPathResponse{}, []marshaller.CharacteristicsPathMatch{}}},
+
+	{Method: http.MethodPost, Path: "/converter/extension-call", Tag: "converter", Summary: "try a converter extension",
+		Request: converter.ExtensionCall{}, Response: converter.ExtensionCallResponse{}},
+
+	{Method: http.MethodGet, Path: "/health/live", Tag: "health", Summary: "liveness", Response: map[string]bool{}},
+	{Method: http.MethodGet, Path: "/health/ready", Tag: "health", Summary: "readiness; responds with 503 if not ready", Response: health.Report{}},
+
+	{Method: http.MethodGet, Path: "/admin/concept-repo", Tag: "admin", Summary: "status of the concept-repo (admin only)", Response: ConceptRepoAdminInfo{}},
+	{Method: http.MethodGet, Path: "/admin/concept-repo/concepts", Tag: "admin", Summary: "concepts of the concept-repo (admin only)", Response: conceptrepo.Dump{}.Concepts},
+	{Method: http.MethodGet, Path: "/admin/concept-repo/characteristics", Tag: "admin", Summary: "characteristics of the concept-repo (admin only)", Response: conceptrepo.Dump{}.Characteristics},
+	{Method: http.MethodGet, Path: "/admin/concept-repo/functions", Tag: "admin", Summary: "function index of the concept-repo (admin only)", Response: map[string]interface{}{}},
+	{Method: http.MethodGet, Path: "/admin/concept-repo/root-characteristics", Tag: "admin", Summary: "root characteristic by characteristic (admin only)", Response: map[string]string{}},
+	{Method: http.MethodPost, Path: "/admin/concept-repo/reload", Tag: "admin", Summary: "reload the concept-repo (admin only); responds with 502 if the reload failed", Response: ConceptRepoAdminInfo{}},
+
+	{Method: http.MethodGet, Path: "/openapi.json", Tag: "documentation", Summary: "this document", Response: map[string]interface{}{}},
+}
+
+func OpenApiEndpoint(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
+	getDocument := sync.OnceValues(func() ([]byte, error) {
+		return json.Marshal(OpenApiDocument())
+	})
+	router.GET("/openapi.json", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		document, err := getDocument()
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_, err = writer.Write(document)
+		if err != nil {
+			config.GetLogger().Error("unable to write response", "error", err)
+		}
+	})
+}
+
+// OpenApiDocument returns the OpenAPI 3 description of all routes; schemas are derived from the go types
+func OpenApiDocument() map[string]interface{} {
+	schemas := &openApiSchemas{components: map[string]interface{}{}, names: map[reflect.Type]string{}}
+	paths := map[string]map[string]interface{}{}
+	for _, r := range routes {
+		openApiPath, pathParams := toOpenApiPath(r.Path)
+		if paths[openApiPath] == nil {
+			paths[openApiPath] = map[string]interface{}{}
+		}
+		parameters := []interface{}{}
+		for _, name := range pathParams {
+			parameters = append(parameters, map[string]interface{}{"name": name, "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}})
+		}
+		for _, param := range r.Query {
+			parameter := map[string]interface{}{"name": param.Name, "in": "query", "required": param.Required, "schema": map[string]interface{}{"type": "string"}}
+			if param.Description != "" {
+				parameter["description"] = param.Description
+			}
+			parameters = append(parameters, parameter)
+		}
+		responses := map[string]interface{}{
+			"200":     jsonContent("OK", schemas.of(r.Response)),
+			"default": map[string]interface{}{"description": "error", "content": map[string]interface{}{"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}}},
+		}
+		for code, body := range r.ErrorBodies {
+			responses[strconv.Itoa(code)] = jsonContent(http.StatusText(code), schemas.of(body))
+		}
+		operation := map[string]interface{}{
+			"operationId": strings.ToLower(r.Method) + operationIdSuffix(r.Path),
+			"summary":     r.Summary,
+			"tags":        []string{r.Tag},
+			"parameters":  parameters,
+			"responses":   responses,
+		}
+		if r.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": schemas.of(r.Request)}},
+			}
+		}
+		paths[openApiPath][strings.ToLower(r.Method)] = operation
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "marshaller",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{}, map[string]interface{}{"bearerAuth": []string{}}},
+	}
+}
+
+func jsonContent(description string, schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}},
+	}
+}
+
+// toOpenApiPath replaces httprouter parameters like ':serviceId' with '{serviceId}'
+func toOpenApiPath(routerPath string) (result string, params []string) {
+	segments := strings.Split(routerPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+var operationIdSeparator = regexp.MustCompile(`[^a-zA-Z0-9]+([a-zA-Z0-9]?)`)
+
+func operationIdSuffix(routerPath string) string {
+	return operationIdSeparator.ReplaceAllStringFunc(routerPath, func(match string) string {
+		return strings.ToUpper(match[len(match)-1:])
+	})
+}
+
+type openApiSchemas struct {
+	components map[string]interface{}
+	names      map[reflect.Type]string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+var schemaNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (this *openApiSchemas) of(value interface{}) interface{} {
+	if alternatives, ok := value.(oneOf); ok {
+		result := []interface{}{}
+		for _, alternative := range alternatives {
+			result = append(result, this.of(alternative))
+		}
+		return map[string]interface{}{"oneOf": result}
+	}
+	if value == nil {
+		return map[string]interface{}{}
+	}
+	return this.schema(reflect.TypeOf(value))
+}
+
+func (this *openApiSchemas) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]interface{}{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": this.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": this.schema(t.Elem())}
+	case reflect.Struct:
+		return this.structSchema(t)
+	}
+	return map[string]interface{}{} //interface{} and other types accept any value
+}
+
+// structSchema registers named structs as component to handle recursive types like model.ContentVariable
+func (this *openApiSchemas) structSchema(t reflect.Type) map[string]interface{} {
+	if t.Name() == "" {
+		return this.structProperties(t)
+	}
+	name, ok := this.names[t]
+	if !ok {
+		name = schemaNameInvalidChars.ReplaceAllString(path.Base(t.PkgPath())+"."+t.Name(), "_")
+		for _, known := range this.names {
+			if known == name {
+				name = schemaNameInvalidChars.ReplaceAllString(t.PkgPath()+"."+t.Name(), "_")
+			}
+		}
+		this.names[t] = name
+		this.components[name] = map[string]interface{}{} //placeholder for recursive references
+		this.components[name] = this.structProperties(t)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func (this *openApiSchemas) structProperties(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := this.structProperties(derefType(field.Type))
+			for key, value := range embedded["properties"].(map[string]interface{}) {
+				properties[key] = value
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = this.schema(field.Type)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
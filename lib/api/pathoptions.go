@@ -23,7 +23,9 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
@@ -35,16 +37,47 @@ func init() {
 	endpoints = append(endpoints, PathOptions)
 }
 
-func PathOptions(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, service *configurables.ConfigurableService, repo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) {
+func PathOptions(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, service *configurables.ConfigurableService, repo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
+
+	getPathOptions := func(writer http.ResponseWriter, repo DeviceRepository, query messages.PathOptionsQuery) {
+		m := marshaller.WithDeviceRepository(repo)
+		result, err, code := m.GetPathOption(query.DeviceTypeIds, query.FunctionId, query.AspectId, query.CharacteristicIdFilter, !query.WithoutEnvelope, query.Direction)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		if len(query.DeviceIds) > 0 {
+			devices, err, code := getDevices(repo, query.DeviceIds)
+			if err != nil {
+				http.Error(writer, err.Error(), code)
+				return
+			}
+			deviceResult, err, code := m.GetDevicePathOption(devices, query.FunctionId, query.AspectId, query.CharacteristicIdFilter, !query.WithoutEnvelope, query.Direction)
+			if err != nil {
+				http.Error(writer, err.Error(), code)
+				return
+			}
+			for deviceId, elements := range deviceResult {
+				result[deviceId] = elements
+			}
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(result)
+	}
 
 	router.GET("/path-options", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		deviceTypeIdsStr := request.URL.Query().Get("device-type-ids")
-		if deviceTypeIdsStr == "" {
+		query := messages.PathOptionsQuery{}
+		if deviceTypeIdsStr := request.URL.Query().Get("device-type-ids"); deviceTypeIdsStr != "" {
+			query.DeviceTypeIds = strings.Split(strings.ReplaceAll(deviceTypeIdsStr, " ", ""), ",")
+		}
+		if deviceIdsStr := request.URL.Query().Get("device-ids"); deviceIdsStr != "" {
+			query.DeviceIds = strings.Split(strings.ReplaceAll(deviceIdsStr, " ", ""), ",")
+		}
+		if len(query.DeviceTypeIds) == 0 && len(query.DeviceIds) == 0 {
 			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
 			json.NewEncoder(writer).Encode(map[string]interface{}{})
 			return
 		}
-		deviceTypeIds := strings.Split(strings.ReplaceAll(deviceTypeIdsStr, " ", ""), ",")
 
 		characteristicIdFilterStr := request.URL.Query().Get("characteristic-filter")
 		if characteristicIdFilterStr == "" {
@@ -52,27 +85,19 @@ func PathOptions(router *httprouter.Router, config config.Config, marshaller *ma
 			json.NewEncoder(writer).Encode(map[string]interface{}{})
 			return
 		}
-		characteristicIdFilter := strings.Split(strings.ReplaceAll(characteristicIdFilterStr, " ", ""), ",")
+		query.CharacteristicIdFilter = strings.Split(strings.ReplaceAll(characteristicIdFilterStr, " ", ""), ",")
 
-		functionId := strings.TrimSpace(request.URL.Query().Get("function-id"))
-		aspectId := strings.TrimSpace(request.URL.Query().Get("aspect-id"))
+		query.FunctionId = strings.TrimSpace(request.URL.Query().Get("function-id"))
+		query.AspectId = strings.TrimSpace(request.URL.Query().Get("aspect-id"))
 
-		withoutEnvelope := false
 		withoutEnvelopeStr := request.URL.Query().Get("function-id")
-		var err error
 		if withoutEnvelopeStr != "" {
-			withoutEnvelope, err = strconv.ParseBool(strings.TrimSpace(withoutEnvelopeStr))
+			query.WithoutEnvelope, _ = strconv.ParseBool(strings.TrimSpace(withoutEnvelopeStr))
 		}
 
-		result, err, code := marshaller.GetPathOption(deviceTypeIds, functionId, aspectId, characteristicIdFilter, !withoutEnvelope)
-		if err != nil {
-			http.Error(writer, err.Error(), code)
-			return
-		} else {
-			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
-			json.NewEncoder(writer).Encode(result)
-			return
-		}
+		query.Direction = strings.TrimSpace(request.URL.Query().Get("direction"))
+
+		getPathOptions(writer, forCaller(config, repo, request), query)
 	})
 
 	router.POST("/query/path-options", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -82,14 +107,102 @@ func PathOptions(router *httprouter.Router, config config.Config, marshaller *ma
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		result, err, code := marshaller.GetPathOption(query.DeviceTypeIds, query.FunctionId, query.AspectId, query.CharacteristicIdFilter, !query.WithoutEnvelope)
+		getPathOptions(writer, forCaller(config, repo, request), query)
+	})
+
+	getPathOptionsV2 := func(writer http.ResponseWriter, repo DeviceRepository, query messages.PathOptionsV2Query) {
+		if query.Direction != "" && query.Direction != model.DirectionInputs && query.Direction != model.DirectionOutputs {
+			http.Error(writer, "unknown direction "+query.Direction, http.StatusBadRequest)
+			return
+		}
+		var aspect *model.AspectNode
+		if query.AspectId != "" {
+			aspectNode, err := repo.GetAspectNode(query.AspectId)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			aspect = &aspectNode
+		}
+		result := map[string][]v2.PathOption{}
+		for _, deviceTypeId := range query.DeviceTypeIds {
+			deviceType, err, code := repo.GetDeviceType(deviceTypeId)
+			if err != nil {
+				http.Error(writer, err.Error(), code)
+				return
+			}
+			result[deviceTypeId], err = marshallerV2.GetPathOptions(repo, deviceType.Services, query.Direction, query.FunctionId, aspect, query.CharacteristicIdFilter)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		devices, err, code := getDevices(repo, query.DeviceIds)
 		if err != nil {
 			http.Error(writer, err.Error(), code)
 			return
-		} else {
-			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
-			json.NewEncoder(writer).Encode(result)
+		}
+		for _, device := range devices {
+			deviceType, err, code := repo.GetDeviceType(device.DeviceTypeId)
+			if err != nil {
+				http.Error(writer, err.Error(), code)
+				return
+			}
+			options, err := marshallerV2.GetPathOptions(repo, model.WithAspectOverrides(deviceType, device).Services, query.Direction, query.FunctionId, aspect, query.CharacteristicIdFilter)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			for i := range options {
+				options[i].DeviceId = device.Id
+				options[i].DeviceName = device.Name
+				options[i].DeviceLocalId = device.LocalId
+			}
+			result[device.Id] = options
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			config.GetLogger().Error("unable to encode response", "error", err)
+		}
+	}
+
+	router.GET("/v2/path-options", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query := messages.PathOptionsV2Query{
+			FunctionId: strings.TrimSpace(request.URL.Query().Get("function-id")),
+			AspectId:   strings.TrimSpace(request.URL.Query().Get("aspect-id")),
+			Direction:  strings.TrimSpace(request.URL.Query().Get("direction")),
+		}
+		if deviceTypeIdsStr := request.URL.Query().Get("device-type-ids"); deviceTypeIdsStr != "" {
+			query.DeviceTypeIds = strings.Split(strings.ReplaceAll(deviceTypeIdsStr, " ", ""), ",")
+		}
+		if deviceIdsStr := request.URL.Query().Get("device-ids"); deviceIdsStr != "" {
+			query.DeviceIds = strings.Split(strings.ReplaceAll(deviceIdsStr, " ", ""), ",")
+		}
+		if characteristicIdFilterStr := request.URL.Query().Get("characteristic-filter"); characteristicIdFilterStr != "" {
+			query.CharacteristicIdFilter = strings.Split(strings.ReplaceAll(characteristicIdFilterStr, " ", ""), ",")
+		}
+		getPathOptionsV2(writer, forCaller(config, repo, request), query)
+	})
+
+	router.POST("/v2/query/path-options", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		query := messages.PathOptionsV2Query{}
+		err := json.NewDecoder(request.Body).Decode(&query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
+		getPathOptionsV2(writer, forCaller(config, repo, request), query)
 	})
 }
+
+func getDevices(repo DeviceRepository, ids []string) (result []model.Device, err error, code int) {
+	for _, id := range ids {
+		device, err, code := repo.GetDevice(id)
+		if err != nil {
+			return nil, err, code
+		}
+		result = append(result, device)
+	}
+	return result, nil, http.StatusOK
+}
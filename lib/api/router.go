@@ -0,0 +1,119 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Route is a registered method and path in httprouter syntax (e.g. '/v2/marshal/:serviceId')
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Router records the routes registered by the endpoints, because httprouter provides no way to list them
+type Router struct {
+	*httprouter.Router
+	routes []Route
+}
+
+func newRouter() *Router {
+	return &Router{Router: httprouter.New()}
+}
+
+func (this *Router) Routes() []Route {
+	return append([]Route{}, this.routes...)
+}
+
+func (this *Router) Handle(method string, path string, handle httprouter.Handle) {
+	this.routes = append(this.routes, Route{Method: method, Path: path})
+	this.Router.Handle(method, path, handle)
+}
+
+func (this *Router) Handler(method string, path string, handler http.Handler) {
+	this.routes = append(this.routes, Route{Method: method, Path: path})
+	this.Router.Handler(method, path, handler)
+}
+
+func (this *Router) HandlerFunc(method string, path string, handler http.HandlerFunc) {
+	this.Handler(method, path, handler)
+}
+
+func (this *Router) GET(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodGet, path, handle)
+}
+
+func (this *Router) HEAD(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodHead, path, handle)
+}
+
+func (this *Router) OPTIONS(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodOptions, path, handle)
+}
+
+func (this *Router) POST(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodPost, path, handle)
+}
+
+func (this *Router) PUT(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodPut, path, handle)
+}
+
+func (this *Router) PATCH(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodPatch, path, handle)
+}
+
+func (this *Router) DELETE(path string, handle httprouter.Handle) {
+	this.Handle(http.MethodDelete, path, handle)
+}
+
+// Pattern returns the registered path matching the request path (e.g. '/v2/marshal/:serviceId' for '/v2/marshal/foo');
+// httprouter allows no conflicting routes, so at most one path matches
+func (this *Router) Pattern(method string, path string) (string, bool) {
+	segments := strings.Split(path, "/")
+	for _, route := range this.routes {
+		if route.Method == method && matchesPath(strings.Split(route.Path, "/"), segments) {
+			return route.Path, true
+		}
+	}
+	return "", false
+}
+
+func matchesPath(pattern []string, segments []string) bool {
+	for i, expected := range pattern {
+		if strings.HasPrefix(expected, "*") {
+			return len(segments) > i
+		}
+		if len(segments) <= i {
+			return false
+		}
+		if strings.HasPrefix(expected, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segments[i] != expected {
+			return false
+		}
+	}
+	return len(segments) == len(pattern)
+}
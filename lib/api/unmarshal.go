@@ -26,6 +26,7 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
 	"github.com/julienschmidt/httprouter"
@@ -35,10 +36,10 @@ func init() {
 	endpoints = append(endpoints, Unmarshalling)
 }
 
-func Unmarshalling(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) {
+func Unmarshalling(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
 	resource := "/unmarshal"
 
-	normalizeRequest := func(request *messages.UnmarshallingRequest) error {
+	normalizeRequest := func(deviceRepo DeviceRepository, request *messages.UnmarshallingRequest) error {
 		if request.Protocol == nil {
 			protocol, err := deviceRepo.GetProtocol(request.Service.ProtocolId)
 			if err != nil {
@@ -74,12 +75,12 @@ func Unmarshalling(router *httprouter.Router, config config.Config, marshaller *
 			return
 		}
 		msg.CharacteristicId = characteristicId
-		msg.Service, err = deviceRepo.GetService(serviceId)
+		msg.Service, err = forCaller(config, deviceRepo, request).GetService(serviceId)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		err = normalizeRequest(&msg)
+		err = normalizeRequest(forCaller(config, deviceRepo, request), &msg)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
@@ -103,7 +104,7 @@ func Unmarshalling(router *httprouter.Router, config config.Config, marshaller *
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		err = normalizeRequest(&msg)
+		err = normalizeRequest(forCaller(config, deviceRepo, request), &msg)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
@@ -29,20 +29,25 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
 	"github.com/julienschmidt/httprouter"
 )
 
+// OutputViolationsHeader lists the violations found by the output validation as json
+const OutputViolationsHeader = "X-Output-Violations"
+
 func init() {
 	endpoints = append(endpoints, UnmarshallingV2)
 }
 
-func UnmarshallingV2(router *httprouter.Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics) {
+func UnmarshallingV2(router *Router, config config.Config, marshaller *marshaller.Marshaller, marshallerV2 *v2.Marshaller, configurableService *configurables.ConfigurableService, deviceRepo DeviceRepository, converter *converter.Converter, metrics *metrics.Metrics, health *health.Health, conceptRepo ConceptRepo) {
 	resource := "/v2/unmarshal"
 
-	normalizeRequest := func(request *messages.UnmarshallingV2Request) error {
+	normalizeRequest := func(deviceRepo DeviceRepository, request *messages.UnmarshallingV2Request) error {
 		config.GetLogger().Debug("UnmarshallingV2Request", "request", fmt.Sprintf("%#v", request))
 		if request.Protocol.Id == "" {
 			protocol, err := deviceRepo.GetProtocol(request.Service.ProtocolId)
@@ -85,8 +90,44 @@ func UnmarshallingV2(router *httprouter.Router, config config.Config, marshaller
 		return nil
 	}
 
-	unmarshal := func(request messages.UnmarshallingV2Request) (interface{}, error) {
-		return marshallerV2.Unmarshal(request.Protocol, request.Service, request.CharacteristicId, request.Path, request.Message, request.SerializedOutput)
+	unmarshal := func(request messages.UnmarshallingV2Request) (interface{}, []validation.Violation, error) {
+		if request.WholeList {
+			return marshallerV2.UnmarshalList(request.Protocol, request.Service, request.CharacteristicId, request.Path, request.Message, request.SerializedOutput)
+		}
+		return marshallerV2.UnmarshalWithViolations(request.Protocol, request.Service, request.CharacteristicId, request.Path, request.Message, request.SerializedOutput)
+	}
+
+	respond := func(writer http.ResponseWriter, msg messages.UnmarshallingV2Request, result interface{}, violations []validation.Violation, err error) (ok bool) {
+		if len(violations) > 0 {
+			policy := marshallerV2.OutputValidationPolicy(msg.Service.Id)
+			metrics.LogOutputViolations(msg.Service.Id, policy, violations)
+			config.GetLogger().Warn("invalid output value", "service", msg.Service.Id, "path", msg.Path, "policy", policy, "violations", fmt.Sprintf("%#v", violations))
+			header, _ := json.Marshal(violations)
+			writer.Header().Set(OutputViolationsHeader, string(header))
+		}
+		if err != nil {
+			var validationErr *validation.Error
+			if errors.As(err, &validationErr) {
+				writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+				writer.WriteHeader(http.StatusUnprocessableEntity)
+				err = json.NewEncoder(writer).Encode(messages.ValidationErrorResponse{
+					Error:      validationErr.Error(),
+					Violations: validationErr.Violations,
+				})
+				if err != nil {
+					config.GetLogger().Error("unable to encode response", "error", err)
+				}
+				return false
+			}
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			config.GetLogger().Error("unable to encode response", "error", err)
+		}
+		return true
 	}
 
 	router.POST(resource+"/:serviceId", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -102,27 +143,20 @@ func UnmarshallingV2(router *httprouter.Router, config config.Config, marshaller
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		msg.Service, err = deviceRepo.GetService(serviceId)
+		msg.Service, err = forCaller(config, deviceRepo, request).GetService(serviceId)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		err = normalizeRequest(&msg)
+		err = normalizeRequest(forCaller(config, deviceRepo, request), &msg)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		result, err := unmarshal(msg)
-		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
-		err = json.NewEncoder(writer).Encode(result)
-		if err != nil {
-			config.GetLogger().Error("unable to encode response", "error", err)
+		result, violations, err := unmarshal(msg)
+		if respond(writer, msg, result, violations, err) {
+			metrics.LogUnmarshallingRequest(request, resource+"/:serviceId", msg, time.Since(start))
 		}
-		metrics.LogUnmarshallingRequest(request, resource+"/:serviceId", msg, time.Since(start))
 	})
 
 	router.POST(resource, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -133,22 +167,15 @@ func UnmarshallingV2(router *httprouter.Router, config config.Config, marshaller
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		err = normalizeRequest(&msg)
+		err = normalizeRequest(forCaller(config, deviceRepo, request), &msg)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
-		result, err := unmarshal(msg)
-		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
-		err = json.NewEncoder(writer).Encode(result)
-		if err != nil {
-			config.GetLogger().Error("unable to encode response", "error", err)
+		result, violations, err := unmarshal(msg)
+		if respond(writer, msg, result, violations, err) {
+			metrics.LogUnmarshallingRequest(request, resource, msg, time.Since(start))
 		}
-		metrics.LogUnmarshallingRequest(request, resource, msg, time.Since(start))
 	})
 
 }
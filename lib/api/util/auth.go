@@ -0,0 +1,113 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/auth"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+// NewAuth verifies tokens if config.AuthJwksUrl is set and checks config.AuthEndpointRoles.
+// Without AuthJwksUrl tokens are expected to be verified by the api gateway.
+func NewAuth(config config.Config, handler http.Handler) *AuthMiddleware {
+	result := &AuthMiddleware{handler: handler, config: config}
+	if config.AuthJwksUrl != "" && config.AuthJwksUrl != "-" {
+		result.verifier = auth.NewVerifier(config.AuthJwksUrl)
+	}
+	return result
+}
+
+type AuthMiddleware struct {
+	handler  http.Handler
+	config   config.Config
+	verifier *auth.Verifier
+}
+
+func (this *AuthMiddleware) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodOptions {
+		this.handler.ServeHTTP(res, req)
+		return
+	}
+	roles := this.requiredRoles(req.URL.Path)
+	if this.verifier == nil && len(roles) == 0 {
+		this.handler.ServeHTTP(res, req)
+		return
+	}
+	if len(roles) == 0 && this.isPublic(req.URL.Path) {
+		this.handler.ServeHTTP(res, req)
+		return
+	}
+	var token auth.Token
+	var err error
+	if this.verifier != nil {
+		token, err = this.verifier.Verify(req.Header.Get("Authorization"))
+	} else {
+		token, err = auth.GetParsedToken(req)
+	}
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if len(roles) > 0 && !hasAnyRole(token, roles) {
+		http.Error(res, "missing role, expected one of: "+strings.Join(roles, ", "), http.StatusForbidden)
+		return
+	}
+	this.handler.ServeHTTP(res, req)
+}
+
+func (this *AuthMiddleware) isPublic(path string) bool {
+	for _, prefix := range this.config.AuthPublicPaths {
+		if matchesPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *AuthMiddleware) requiredRoles(path string) (result []string) {
+	longest := -1
+	for prefix, roles := range this.config.AuthEndpointRoles {
+		if matchesPrefix(path, prefix) && len(prefix) > longest {
+			longest = len(prefix)
+			result = nil
+			for _, role := range strings.Split(roles, "|") {
+				if role = strings.TrimSpace(role); role != "" {
+					result = append(result, role)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// matchesPrefix only matches whole path segments ("/admin" matches "/admin/x" but not "/administration")
+func matchesPrefix(path string, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func hasAnyRole(token auth.Token, roles []string) bool {
+	for _, role := range roles {
+		if token.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
@@ -16,29 +16,139 @@
 
 package util
 
-import "net/http"
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
-func NewCors(handler http.Handler) *CorsMiddleware {
-	return &CorsMiddleware{handler: handler}
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+var DefaultCorsAllowedHeaders = []string{"Origin", "X-Requested-With", "Content-Type", "Accept", "Authorization"}
+var DefaultCorsAllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+
+// NewCors applies the cors policy of the config.
+// Without config.CorsAllowedOrigins no cross-origin request is allowed.
+// Origins may be exact ("https://ui.example.com"), patterns ("https://*.example.com") or "*" for any origin;
+// credentials are never allowed for origins only matched by "*".
+func NewCors(config config.Config, handler http.Handler) *CorsMiddleware {
+	result := &CorsMiddleware{
+		handler:          handler,
+		allowedHeaders:   config.CorsAllowedHeaders,
+		allowedMethods:   config.CorsAllowedMethods,
+		exposedHeaders:   config.CorsExposedHeaders,
+		allowCredentials: config.CorsAllowCredentials,
+	}
+	if len(result.allowedHeaders) == 0 {
+		result.allowedHeaders = DefaultCorsAllowedHeaders
+	}
+	if len(result.allowedMethods) == 0 {
+		result.allowedMethods = DefaultCorsAllowedMethods
+	}
+	if config.CorsMaxAge > 0 {
+		result.maxAge = strconv.FormatInt(config.CorsMaxAge, 10)
+	}
+	for _, origin := range config.CorsAllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		switch {
+		case origin == "*":
+			result.allowAny = true
+		case strings.Contains(origin, "*"):
+			pattern := strings.ReplaceAll(regexp.QuoteMeta(strings.ToLower(origin)), `\*`, `[^/]*`)
+			result.originPatterns = append(result.originPatterns, regexp.MustCompile("^"+pattern+"$"))
+		case origin != "":
+			result.origins = append(result.origins, strings.ToLower(origin))
+		}
+	}
+	return result
 }
 
 type CorsMiddleware struct {
-	handler http.Handler
+	handler          http.Handler
+	origins          []string
+	originPatterns   []*regexp.Regexp
+	allowAny         bool
+	allowedHeaders   []string
+	allowedMethods   []string
+	exposedHeaders   []string
+	allowCredentials bool
+	maxAge           string
 }
 
 func (this *CorsMiddleware) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 	origin := req.Header.Get("Origin")
 	if origin == "" {
-		origin = "*"
+		this.handler.ServeHTTP(res, req)
+		return
+	}
+	res.Header().Add("Vary", "Origin")
+	preflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+	allowed, explicit := this.allowedOrigin(origin)
+	if preflight {
+		res.Header().Add("Vary", "Access-Control-Request-Method")
+		res.Header().Add("Vary", "Access-Control-Request-Headers")
+		if !allowed || !this.allowedRequest(req) {
+			http.Error(res, "cors request not allowed", http.StatusForbidden)
+			return
+		}
+		this.setOriginHeaders(res, origin, explicit)
+		res.Header().Set("Access-Control-Allow-Methods", strings.Join(this.allowedMethods, ", "))
+		res.Header().Set("Access-Control-Allow-Headers", strings.Join(this.allowedHeaders, ", "))
+		if this.maxAge != "" {
+			res.Header().Set("Access-Control-Max-Age", this.maxAge)
+		}
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if allowed {
+		this.setOriginHeaders(res, origin, explicit)
+		if len(this.exposedHeaders) > 0 {
+			res.Header().Set("Access-Control-Expose-Headers", strings.Join(this.exposedHeaders, ", "))
+		}
+	}
+	this.handler.ServeHTTP(res, req)
+}
+
+func (this *CorsMiddleware) setOriginHeaders(res http.ResponseWriter, origin string, explicit bool) {
+	if !explicit {
+		res.Header().Set("Access-Control-Allow-Origin", "*")
+		return
 	}
 	res.Header().Set("Access-Control-Allow-Origin", origin)
-	res.Header().Set("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, authorization, Authorization")
-	res.Header().Set("Access-Control-Allow-Credentials", "true")
-	res.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+	if this.allowCredentials {
+		res.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
 
-	if req.Method == "OPTIONS" {
-		res.WriteHeader(http.StatusOK)
-	} else {
-		this.handler.ServeHTTP(res, req)
+// allowedOrigin returns explicit=false if the origin is only allowed by "*"
+func (this *CorsMiddleware) allowedOrigin(origin string) (allowed bool, explicit bool) {
+	origin = strings.ToLower(origin)
+	if slices.Contains(this.origins, origin) {
+		return true, true
+	}
+	for _, pattern := range this.originPatterns {
+		if pattern.MatchString(origin) {
+			return true, true
+		}
+	}
+	return this.allowAny, false
+}
+
+func (this *CorsMiddleware) allowedRequest(req *http.Request) bool {
+	method := strings.ToUpper(strings.TrimSpace(req.Header.Get("Access-Control-Request-Method")))
+	if !slices.ContainsFunc(this.allowedMethods, func(allowed string) bool { return strings.EqualFold(allowed, method) }) {
+		return false
+	}
+	for _, header := range strings.Split(req.Header.Get("Access-Control-Request-Headers"), ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		if !slices.ContainsFunc(this.allowedHeaders, func(allowed string) bool { return strings.EqualFold(allowed, header) }) {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"net/http"
+)
+
+type RequestMetrics interface {
+	LogRequest(endpoint string, method string, code int)
+}
+
+// RoutePatterns returns the registered route path matching a request (e.g. '/v2/marshal/:serviceId')
+type RoutePatterns interface {
+	Pattern(method string, path string) (string, bool)
+}
+
+// NewRequestMetrics counts every request handled by handler by the matching route of routes;
+// requests without route are counted as endpoint 'unknown' to limit the label values
+func NewRequestMetrics(metrics RequestMetrics, routes RoutePatterns, handler http.Handler) *RequestMetricsMiddleware {
+	return &RequestMetricsMiddleware{metrics: metrics, routes: routes, handler: handler}
+}
+
+type RequestMetricsMiddleware struct {
+	metrics RequestMetrics
+	routes  RoutePatterns
+	handler http.Handler
+}
+
+func (this *RequestMetricsMiddleware) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: res, code: http.StatusOK}
+	this.handler.ServeHTTP(recorder, req)
+	this.metrics.LogRequest(this.endpoint(req), req.Method, recorder.code)
+}
+
+func (this *RequestMetricsMiddleware) endpoint(req *http.Request) string {
+	method := req.Method
+	if method == http.MethodOptions {
+		method = req.Header.Get("Access-Control-Request-Method")
+	}
+	pattern, ok := this.routes.Pattern(method, req.URL.Path)
+	if !ok {
+		return "unknown"
+	}
+	return pattern
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (this *statusRecorder) WriteHeader(code int) {
+	if !this.wroteHeader {
+		this.code = code
+		this.wroteHeader = true
+	}
+	this.ResponseWriter.WriteHeader(code)
+}
+
+func (this *statusRecorder) Write(b []byte) (int, error) {
+	this.wroteHeader = true
+	return this.ResponseWriter.Write(b)
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Verifier checks token signatures against the keys of a JWKS.
+// The location may be a http(s) url or a local file (optionally prefixed with file://).
+type Verifier struct {
+	location       string
+	mux            sync.Mutex
+	keys           map[string]interface{}
+	loaded         time.Time
+	ReloadInterval time.Duration //min duration between reloads triggered by unknown key ids
+	Now            func() time.Time
+}
+
+func NewVerifier(location string) *Verifier {
+	return &Verifier{location: location, ReloadInterval: 10 * time.Second, Now: time.Now}
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type timeClaims struct {
+	Exp *float64 `json:"exp"`
+	Nbf *float64 `json:"nbf"`
+}
+
+// Verify checks signature, exp and nbf of the token and returns its claims
+func (this *Verifier) Verify(token string) (result Token, err error) {
+	result, err = Parse(token)
+	if err != nil {
+		return result, err
+	}
+	parts := strings.Split(strings.TrimPrefix(result.Token, "Bearer "), ".")
+	h := header{}
+	err = decodeSegment(parts[0], &h)
+	if err != nil {
+		return result, ErrInvalidToken
+	}
+	hash, err := hashOf(h.Alg)
+	if err != nil {
+		return result, err
+	}
+	key, err := this.getKey(h.Kid)
+	if err != nil {
+		return result, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return result, ErrInvalidToken
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	digest := hasher.Sum(nil)
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(h.Alg, "RS") || rsa.VerifyPKCS1v15(k, hash, digest, signature) != nil {
+			return result, ErrInvalidToken
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(h.Alg, "ES") || len(signature) != 2*size {
+			return result, ErrInvalidToken
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return result, ErrInvalidToken
+		}
+	default:
+		return result, ErrInvalidToken
+	}
+	claims := timeClaims{}
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return result, ErrInvalidToken
+	}
+	now := float64(this.Now().Unix())
+	if claims.Exp != nil && now >= *claims.Exp {
+		return result, errors.New("token expired")
+	}
+	if claims.Nbf != nil && now < *claims.Nbf {
+		return result, errors.New("token not yet valid")
+	}
+	return result, nil
+}
+
+func (this *Verifier) getKey(kid string) (key interface{}, err error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	key, ok := this.lookup(kid)
+	if ok {
+		return key, nil
+	}
+	if this.keys != nil && this.Now().Sub(this.loaded) < this.ReloadInterval {
+		return nil, errors.New("unknown token key")
+	}
+	keys, err := this.load()
+	if err != nil {
+		return nil, err
+	}
+	this.keys = keys
+	this.loaded = this.Now()
+	key, ok = this.lookup(kid)
+	if !ok {
+		return nil, errors.New("unknown token key")
+	}
+	return key, nil
+}
+
+// lookup uses the only key of the set if the token names none
+func (this *Verifier) lookup(kid string) (key interface{}, ok bool) {
+	key, ok = this.keys[kid]
+	if !ok && kid == "" && len(this.keys) == 1 {
+		for _, key = range this.keys {
+			return key, true
+		}
+	}
+	return key, ok
+}
+
+func (this *Verifier) load() (result map[string]interface{}, err error) {
+	var body []byte
+	if strings.HasPrefix(this.location, "http://") || strings.HasPrefix(this.location, "https://") {
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Get(this.location)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New("unable to load jwks: unexpected status code")
+		}
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		body, err = os.ReadFile(strings.TrimPrefix(this.location, "file://"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	set := jwks{}
+	err = json.Unmarshal(body, &set)
+	if err != nil {
+		return nil, err
+	}
+	result = map[string]interface{}{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, err
+		}
+		result[k.Kid] = key
+	}
+	return result, nil
+}
+
+func (this jwk) publicKey() (interface{}, error) {
+	switch this.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(this.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(this.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch this.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("unsupported jwk curve " + this.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(this.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(this.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, errors.New("unsupported jwk key type " + this.Kty)
+}
+
+func hashOf(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "ES512":
+		return crypto.SHA512, nil
+	}
+	return 0, errors.New("unsupported token algorithm " + alg)
+}
+
+func decodeSegment(segment string, result interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, result)
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var ErrMissingToken = errors.New("missing authorization token")
+
+type Token struct {
+	Token       string      `json:"-"`
+	Sub         string      `json:"sub,omitempty"`
+	RealmAccess RealmAccess `json:"realm_access,omitempty"`
+}
+
+type RealmAccess struct {
+	Roles []string `json:"roles"`
+}
+
+func (this Token) HasRole(role string) bool {
+	return slices.Contains(this.RealmAccess.Roles, role)
+}
+
+func (this Token) IsAdmin() bool {
+	return this.HasRole("admin")
+}
+
+// GetParsedToken reads the claims of the bearer token in the Authorization header;
+// the signature is not checked, the token is expected to be verified by the api gateway
+func GetParsedToken(req *http.Request) (token Token, err error) {
+	return Parse(req.Header.Get("Authorization"))
+}
+
+func Parse(token string) (result Token, err error) {
+	if token == "" {
+		return result, ErrMissingToken
+	}
+	if len(token) > 7 && strings.ToLower(token[:7]) == "bearer " {
+		token = token[7:]
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return result, errors.New("invalid token format")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(payload, &result)
+	if err != nil {
+		return result, err
+	}
+	result.Token = "Bearer " + token
+	return result, nil
+}
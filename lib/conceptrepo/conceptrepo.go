@@ -19,9 +19,10 @@ package conceptrepo
 import (
 	"context"
 	"errors"
-	"fmt"
+	"net/http"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/SENERGY-Platform/marshaller/lib/config"
@@ -32,17 +33,16 @@ import (
 type ConceptRepo struct {
 	config config.Config
 	access Access
+	client *http.Client
 
-	defaults                           []ConceptRepoDefault
-	concepts                           map[string]model.Concept
-	characteristics                    map[string]model.Characteristic
-	conceptByCharacteristic            map[string][]model.Concept
-	rootCharacteristicByCharacteristic map[string]model.Characteristic
+	defaults []ConceptRepoDefault
 
-	characteristicsOfFunction map[string][]string
-	functionToConcept         map[string]string
+	//readers use the current index without locking; writers build a new index and swap it in
+	index atomic.Pointer[index]
 
-	mux sync.Mutex
+	updateMux sync.Mutex //serializes Load() and incremental refreshes, which read and replace the index
+
+	stats stats
 }
 
 type ConceptRepoDefault struct {
@@ -56,19 +56,23 @@ type Access interface {
 
 func New(ctx context.Context, conf config.Config, access Access, defaults ...ConceptRepoDefault) (result *ConceptRepo, err error) {
 	result = &ConceptRepo{
-		config:                             conf,
-		access:                             access,
-		defaults:                           defaults,
-		concepts:                           map[string]model.Concept{},
-		characteristics:                    map[string]model.Characteristic{},
-		conceptByCharacteristic:            map[string][]model.Concept{},
-		rootCharacteristicByCharacteristic: map[string]model.Characteristic{},
-		characteristicsOfFunction:          map[string][]string{},
-		functionToConcept:                  map[string]string{},
+		config:   conf,
+		access:   access,
+		client:   config.HttpClient(access),
+		defaults: defaults,
 	}
+	result.index.Store(newIndex())
 	err = result.Load()
 	if err != nil {
-		return result, err
+		snapshotErr := result.LoadSnapshot()
+		if snapshotErr != nil {
+			if !errors.Is(snapshotErr, ErrSnapshotDisabled) {
+				conf.GetLogger().Warn("unable to use concept-repo snapshot for warm start", "file", conf.ConceptRepoSnapshotFile, "error", snapshotErr)
+			}
+			return result, err
+		}
+		conf.GetLogger().Warn("unable to load concept repository, use snapshot until the device-repository is available", "error", err, "snapshot_age", result.SnapshotAge().String())
+		go result.retryLoad(ctx)
 	}
 	ticker := time.NewTicker(time.Duration(conf.ConceptRepoRefreshInterval) * time.Second)
 	go func() {
@@ -77,7 +81,7 @@ func New(ctx context.Context, conf config.Config, access Access, defaults ...Con
 	}()
 	refresh := func() {
 		conf.GetLogger().Info("refresh concept-repo")
-		err = result.Load()
+		err := result.Load()
 		if err != nil {
 			conf.GetLogger().Warn("unable to update concept repository", "error", err)
 		}
@@ -87,21 +91,82 @@ func New(ctx context.Context, conf config.Config, access Access, defaults ...Con
 			refresh()
 		}
 	}()
-	f := func(_ string, _ *sync.WaitGroup) {
-		refresh()
+	incremental := func(kind string, update func(id string) error) func(string, *sync.WaitGroup) {
+		return func(id string, _ *sync.WaitGroup) {
+			if id == "" {
+				refresh()
+				return
+			}
+			conf.GetLogger().Info("incremental concept-repo refresh", "kind", kind, "id", id)
+			err := update(id)
+			if err != nil {
+				conf.GetLogger().Warn("unable to refresh concept-repo incrementally", "kind", kind, "id", id, "error", err)
+			}
+		}
 	}
-	signal.Known.CacheInvalidationAll.Sub("concept-repo-all", f)
-	signal.Known.CharacteristicCacheInvalidation.Sub("concept-repo-characteristics", f)
-	signal.Known.ConceptCacheInvalidation.Sub("concept-repo-concept", f)
-	signal.Known.FunctionCacheInvalidation.Sub("concept-repo-function", f)
+	signal.Known.CacheInvalidationAll.Sub("concept-repo-all", func(_ string, _ *sync.WaitGroup) {
+		refresh()
+	})
+	signal.Known.CharacteristicCacheInvalidation.Sub("concept-repo-characteristics", incremental("characteristic", result.RefreshCharacteristic))
+	signal.Known.ConceptCacheInvalidation.Sub("concept-repo-concept", incremental("concept", result.RefreshConcept))
+	signal.Known.FunctionCacheInvalidation.Sub("concept-repo-function", incremental("function", result.RefreshFunction))
 	return result, nil
 }
 
+// retryLoad is used after a warm start from the snapshot file, to replace the snapshot as soon as the device-repository is reachable
+func (this *ConceptRepo) retryLoad(ctx context.Context) {
+	interval := time.Duration(this.config.ConceptRepoRetryInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !this.Status().LoadedFromSnapshotFile {
+				return
+			}
+			err := this.Load()
+			if err != nil {
+				this.config.GetLogger().Warn("unable to replace concept-repo snapshot", "error", err)
+				continue
+			}
+			this.config.GetLogger().Info("replaced concept-repo snapshot with current device-repository state")
+			return
+		}
+	}
+}
+
+type Status struct {
+	LoadedAt               time.Time `json:"loaded_at"`
+	LoadedFromSnapshotFile bool      `json:"loaded_from_snapshot_file"`
+	SnapshotAgeSeconds     float64   `json:"snapshot_age_seconds"`
+	Concepts               int       `json:"concepts"` //without defaults
+	Functions              int       `json:"functions"`
+}
+
+func (this *ConceptRepo) Status() Status {
+	current := this.index.Load()
+	return Status{
+		LoadedAt:               current.source.LoadedAt,
+		LoadedFromSnapshotFile: current.fromSnapshotFile,
+		SnapshotAgeSeconds:     current.snapshotAge().Seconds(),
+		Concepts:               len(current.source.Concepts),
+		Functions:              len(current.source.Functions),
+	}
+}
+
+// SnapshotAge returns the time since the currently used state was fetched from the device-repository
+func (this *ConceptRepo) SnapshotAge() time.Duration {
+	return this.index.Load().snapshotAge()
+}
+
 func (this *ConceptRepo) GetCharacteristicsOfFunction(functionId string) (characteristicIds []string, err error) {
-	this.mux.Lock()
-	defer this.mux.Unlock()
 	var ok bool
-	characteristicIds, ok = this.characteristicsOfFunction[functionId]
+	characteristicIds, ok = this.index.Load().characteristicsOfFunction[functionId]
 	if !ok {
 		err = errors.New("unknown function-id")
 	}
@@ -109,9 +174,7 @@ func (this *ConceptRepo) GetCharacteristicsOfFunction(functionId string) (charac
 }
 
 func (this *ConceptRepo) GetConcept(id string) (concept model.Concept, err error) {
-	this.mux.Lock()
-	defer this.mux.Unlock()
-	concept, ok := this.concepts[id]
+	concept, ok := this.index.Load().concepts[id]
 	if !ok {
 		debug.PrintStack()
 		return concept, errors.New("no concept found for id " + id)
@@ -120,9 +183,7 @@ func (this *ConceptRepo) GetConcept(id string) (concept model.Concept, err error
 }
 
 func (this *ConceptRepo) GetConceptIdOfFunction(id string) string {
-	this.mux.Lock()
-	defer this.mux.Unlock()
-	return this.functionToConcept[id]
+	return this.index.Load().functionToConcept[id]
 }
 
 func getCharacteristicDescendents(characteristic model.Characteristic) (result []model.Characteristic) {
@@ -134,9 +195,8 @@ func getCharacteristicDescendents(characteristic model.Characteristic) (result [
 }
 
 func (this *ConceptRepo) GetConceptsOfCharacteristic(characteristicId string) (conceptIds []string, err error) {
-	this.mux.Lock()
-	defer this.mux.Unlock()
-	concepts, ok := this.conceptByCharacteristic[this.rootCharacteristicByCharacteristic[characteristicId].Id]
+	current := this.index.Load()
+	concepts, ok := current.conceptByCharacteristic[current.rootCharacteristicByCharacteristic[characteristicId].Id]
 	if !ok {
 		debug.PrintStack()
 		return conceptIds, errors.New("no concept found for characteristic id " + characteristicId)
@@ -151,9 +211,7 @@ func (this *ConceptRepo) GetCharacteristic(id string) (characteristic model.Char
 	if id == "" {
 		return model.NullCharacteristic, nil
 	}
-	this.mux.Lock()
-	defer this.mux.Unlock()
-	characteristic, ok := this.characteristics[id]
+	characteristic, ok := this.index.Load().characteristics[id]
 	if !ok {
 		debug.PrintStack()
 		return characteristic, errors.New("no characteristic found for id " + id)
@@ -162,25 +220,12 @@ func (this *ConceptRepo) GetCharacteristic(id string) (characteristic model.Char
 }
 
 func (this *ConceptRepo) GetRootCharacteristics(ids []string) (result []string) {
-	this.mux.Lock()
-	defer this.mux.Unlock()
+	current := this.index.Load()
 	for _, id := range ids {
-		root, ok := this.rootCharacteristicByCharacteristic[id]
+		root, ok := current.rootCharacteristicByCharacteristic[id]
 		if ok {
 			result = append(result, root.Id)
 		}
 	}
 	return
 }
-
-func (this *ConceptRepo) registerFunction(f FunctionInfo) {
-	if f.ConceptId != "" {
-		concept, ok := this.concepts[f.ConceptId]
-		if !ok {
-			this.config.GetLogger().Warn("unable to register function with unknown concept", "function", fmt.Sprintf("%#v", f))
-			return
-		}
-		this.characteristicsOfFunction[f.Id] = concept.CharacteristicIds
-		this.functionToConcept[f.Id] = f.ConceptId
-	}
-}
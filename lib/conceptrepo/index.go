@@ -0,0 +1,146 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conceptrepo
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// index is built completely before it is published by the ConceptRepo and never changed afterward,
+// which allows readers to use it without locks
+type index struct {
+	source           Snapshot
+	fromSnapshotFile bool
+
+	concepts                           map[string]model.Concept
+	characteristics                    map[string]model.Characteristic
+	conceptByCharacteristic            map[string][]model.Concept
+	rootCharacteristicByCharacteristic map[string]model.Characteristic
+
+	characteristicsOfFunction map[string][]string
+	functionToConcept         map[string]string
+}
+
+func newIndex() *index {
+	return &index{
+		concepts:                           map[string]model.Concept{},
+		characteristics:                    map[string]model.Characteristic{},
+		conceptByCharacteristic:            map[string][]model.Concept{},
+		rootCharacteristicByCharacteristic: map[string]model.Characteristic{},
+		characteristicsOfFunction:          map[string][]string{},
+		functionToConcept:                  map[string]string{},
+	}
+}
+
+func buildIndex(logger *slog.Logger, defaults []ConceptRepoDefault, source Snapshot, fromSnapshotFile bool) *index {
+	result := newIndex()
+	result.source = source
+	result.fromSnapshotFile = fromSnapshotFile
+	for _, defaultElement := range defaults {
+		result.register(logger, defaultElement.Concept, defaultElement.Characteristics)
+	}
+	for _, element := range source.Concepts {
+		result.register(logger, element.Concept, element.Characteristics)
+	}
+	for _, f := range source.Functions {
+		result.registerFunction(logger, f)
+	}
+	return result
+}
+
+func (this *index) snapshotAge() time.Duration {
+	if this.source.LoadedAt.IsZero() {
+		return 0
+	}
+	return time.Since(this.source.LoadedAt)
+}
+
+func (this *index) register(logger *slog.Logger, concept model.Concept, characteristics []model.Characteristic) {
+	logger.Debug("load concept", "concept", concept.Name, "id", concept.Id)
+	//the given ids may be shared with an already published index
+	concept.CharacteristicIds = slices.Clone(concept.CharacteristicIds)
+	for _, characteristic := range characteristics {
+		logger.Debug("load characteristic", "concept", concept.Name, "characteristic", characteristic.Name, "id", characteristic.Id)
+		concept.CharacteristicIds = append(concept.CharacteristicIds, characteristic.Id)
+		this.characteristics[characteristic.Id] = characteristic
+		this.conceptByCharacteristic[characteristic.Id] = append(this.conceptByCharacteristic[characteristic.Id], concept)
+		this.rootCharacteristicByCharacteristic[characteristic.Id] = characteristic
+		for _, descendent := range getCharacteristicDescendents(characteristic) {
+			this.rootCharacteristicByCharacteristic[descendent.Id] = characteristic
+			this.characteristics[descendent.Id] = descendent
+		}
+	}
+	this.concepts[concept.Id] = concept
+}
+
+func (this *index) registerFunction(logger *slog.Logger, f FunctionInfo) {
+	if f.ConceptId != "" {
+		concept, ok := this.concepts[f.ConceptId]
+		if !ok {
+			logger.Warn("unable to register function with unknown concept", "function", fmt.Sprintf("%#v", f))
+			return
+		}
+		this.characteristicsOfFunction[f.Id] = concept.CharacteristicIds
+		this.functionToConcept[f.Id] = f.ConceptId
+	}
+}
+
+// Dump exposes the current index for inspection
+type Dump struct {
+	Concepts                           map[string]model.Concept        `json:"concepts"`
+	Characteristics                    map[string]model.Characteristic `json:"characteristics"`
+	RootCharacteristicByCharacteristic map[string]string               `json:"root_characteristic_by_characteristic"`
+	FunctionToConcept                  map[string]string               `json:"function_to_concept"`
+	CharacteristicsOfFunction          map[string][]string             `json:"characteristics_of_function"`
+}
+
+// Dump returns the content of the index currently used for reads; the index is never changed, so the maps are shared
+func (this *ConceptRepo) Dump() Dump {
+	current := this.index.Load()
+	result := Dump{
+		Concepts:                           current.concepts,
+		Characteristics:                    current.characteristics,
+		RootCharacteristicByCharacteristic: map[string]string{},
+		FunctionToConcept:                  current.functionToConcept,
+		CharacteristicsOfFunction:          current.characteristicsOfFunction,
+	}
+	for id, root := range current.rootCharacteristicByCharacteristic {
+		result.RootCharacteristicByCharacteristic[id] = root.Id
+	}
+	return result
+}
+
+// Sizes counts the entries of the index currently used for reads
+type Sizes struct {
+	Concepts        int `json:"concepts"`
+	Characteristics int `json:"characteristics"` //including sub characteristics
+	Functions       int `json:"functions"`
+}
+
+func (this *ConceptRepo) Sizes() Sizes {
+	current := this.index.Load()
+	return Sizes{
+		Concepts:        len(current.concepts),
+		Characteristics: len(current.characteristics),
+		Functions:       len(current.functionToConcept),
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conceptrepo
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+func TestBuildIndexDoesNotChangePublishedIndex(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	ids := make([]string, 0, 4)
+	source := func(characteristicIds ...string) Snapshot {
+		element := SnapshotElement{Concept: model.Concept{Id: "concept", CharacteristicIds: ids}}
+		for _, id := range characteristicIds {
+			element.Characteristics = append(element.Characteristics, model.Characteristic{Id: id})
+		}
+		return Snapshot{
+			Concepts:  []SnapshotElement{element},
+			Functions: []FunctionInfo{{Id: "function", ConceptId: "concept"}},
+		}
+	}
+
+	published := buildIndex(logger, nil, source("a", "b"), false)
+	buildIndex(logger, nil, source("c", "d"), false)
+
+	expected := []string{"a", "b"}
+	if actual := published.concepts["concept"].CharacteristicIds; !slices.Equal(actual, expected) {
+		t.Errorf("concept: %#v != %#v", actual, expected)
+	}
+	if actual := published.characteristicsOfFunction["function"]; !slices.Equal(actual, expected) {
+		t.Errorf("function: %#v != %#v", actual, expected)
+	}
+}
+
+func TestCloneSnapshotElements(t *testing.T) {
+	elements := []SnapshotElement{{
+		Concept:         model.Concept{Id: "concept", CharacteristicIds: []string{"a"}},
+		Characteristics: []model.Characteristic{{Id: "a"}},
+	}}
+	clone := cloneSnapshotElements(elements)
+	clone[0].Concept.CharacteristicIds[0] = "b"
+	clone[0].Characteristics[0].Id = "b"
+	if elements[0].Concept.CharacteristicIds[0] != "a" || elements[0].Characteristics[0].Id != "a" {
+		t.Errorf("%#v", elements)
+	}
+}
@@ -17,88 +17,83 @@
 package conceptrepo
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"reflect"
+	"time"
 
 	"github.com/SENERGY-Platform/device-repository/lib/client"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	"github.com/SENERGY-Platform/models/go/models"
 )
 
-func (this *ConceptRepo) Load() error {
-	conceptIds, err := this.loadConceptIds()
+func (this *ConceptRepo) Load() (err error) {
+	this.updateMux.Lock()
+	defer this.updateMux.Unlock()
+	defer this.stats.record("full", "", time.Now(), &err)
+	snapshot, err := this.fetch()
 	if err != nil {
 		return err
 	}
+	this.checkConsistency(snapshot)
+	this.apply(snapshot, false)
+	err = this.writeSnapshot(snapshot)
+	if err != nil {
+		this.config.GetLogger().Warn("unable to write concept-repo snapshot", "file", this.config.ConceptRepoSnapshotFile, "error", err)
+	}
+	return nil
+}
 
-	type Temp struct {
-		Concept         model.Concept
-		Characteristics []model.Characteristic
+// checkConsistency logs differences between the incrementally maintained state and a complete reload
+func (this *ConceptRepo) checkConsistency(snapshot Snapshot) {
+	current := this.index.Load().source
+	if current.LoadedAt.IsZero() {
+		return
+	}
+	if !reflect.DeepEqual(current.conceptsById(), snapshot.conceptsById()) || !reflect.DeepEqual(current.functionsById(), snapshot.functionsById()) {
+		this.config.GetLogger().Warn("concept-repo state differs from full reload; incremental refresh missed updates")
+	}
+}
+
+func (this *ConceptRepo) fetch() (snapshot Snapshot, err error) {
+	snapshot.LoadedAt = time.Now()
+	conceptIds, err := this.loadConceptIds()
+	if err != nil {
+		return snapshot, err
 	}
-	temp := []Temp{}
 
 	for _, conceptId := range conceptIds {
 		concept, err := this.loadConcept(conceptId)
 		if err != nil {
-			return err
+			return snapshot, err
 		}
-		element := Temp{
+		element := SnapshotElement{
 			Concept: concept,
 		}
 		for _, characteristicId := range concept.CharacteristicIds {
 			characteristic, err := this.loadCharacteristic(characteristicId)
 			if err != nil {
-				return err
+				return snapshot, err
 			}
 			element.Characteristics = append(element.Characteristics, characteristic)
 		}
-		temp = append(temp, element)
+		snapshot.Concepts = append(snapshot.Concepts, element)
 	}
 
-	functionInfos, err := this.loadFunctions()
+	snapshot.Functions, err = this.loadFunctions()
 	if err != nil {
-		return err
-	}
-
-	this.mux.Lock()
-	defer this.mux.Unlock()
-
-	this.resetToDefault()
-
-	for _, element := range temp {
-		this.register(element.Concept, element.Characteristics)
-	}
-
-	for _, f := range functionInfos {
-		this.registerFunction(f)
+		return snapshot, err
 	}
-
-	return nil
+	return snapshot, nil
 }
 
-func (this *ConceptRepo) resetToDefault() {
-	this.concepts = map[string]model.Concept{}
-	this.characteristics = map[string]model.Characteristic{}
-	this.conceptByCharacteristic = map[string][]model.Concept{}
-	this.rootCharacteristicByCharacteristic = map[string]model.Characteristic{}
-	for _, defaultElement := range this.defaults {
-		this.register(defaultElement.Concept, defaultElement.Characteristics)
-	}
-}
-
-func (this *ConceptRepo) register(concept model.Concept, characteristics []model.Characteristic) {
-	this.config.GetLogger().Info("load concept", "concept", concept.Name, "id", concept.Id)
-	for _, characteristic := range characteristics {
-		this.config.GetLogger().Info("load characteristic", "concept", concept.Name, "characteristic", characteristic.Name, "id", characteristic.Id)
-		concept.CharacteristicIds = append(concept.CharacteristicIds, characteristic.Id)
-		this.characteristics[characteristic.Id] = characteristic
-		this.conceptByCharacteristic[characteristic.Id] = append(this.conceptByCharacteristic[characteristic.Id], concept)
-		this.rootCharacteristicByCharacteristic[characteristic.Id] = characteristic
-		for _, descendent := range getCharacteristicDescendents(characteristic) {
-			this.rootCharacteristicByCharacteristic[descendent.Id] = characteristic
-			this.characteristics[descendent.Id] = descendent
-		}
-	}
-	this.concepts[concept.Id] = concept
+// apply builds the index for snapshot and publishes it in one step;
+// the caller is expected to hold updateMux or to be the only writer
+func (this *ConceptRepo) apply(snapshot Snapshot, fromSnapshotFile bool) {
+	this.index.Store(buildIndex(this.config.GetLogger(), this.defaults, snapshot, fromSnapshotFile))
 }
 
 type IdWrapper struct {
@@ -163,7 +158,7 @@ func (this *ConceptRepo) loadConcept(id string) (result model.Concept, err error
 	if err != nil {
 		return result, err
 	}
-	err = token.GetJSON(this.config.DeviceRepositoryUrl+"/concepts/"+url.PathEscape(id), &result)
+	err = token.GetJSONWithClient(this.client, this.config.DeviceRepositoryUrl+"/concepts/"+url.PathEscape(id), &result)
 	return
 }
 
@@ -172,6 +167,27 @@ func (this *ConceptRepo) loadCharacteristic(id string) (result model.Characteris
 	if err != nil {
 		return result, err
 	}
-	err = token.GetJSON(this.config.DeviceRepositoryUrl+"/characteristics/"+url.PathEscape(id), &result)
+	err = token.GetJSONWithClient(this.client, this.config.DeviceRepositoryUrl+"/characteristics/"+url.PathEscape(id), &result)
 	return
 }
+
+// loadJSON returns found == false if the device-repository responds with http.StatusNotFound
+func (this *ConceptRepo) loadJSON(path string, result interface{}) (found bool, err error) {
+	token, err := this.access.Ensure()
+	if err != nil {
+		return false, err
+	}
+	resp, err := token.GetWithClient(this.client, this.config.DeviceRepositoryUrl+path)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= 300 {
+		temp, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("unexpected response from device-repository for %v: %v %v", path, resp.StatusCode, string(temp))
+	}
+	return true, json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conceptrepo
+
+import (
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// RefreshConcept reloads the concept and its characteristics; a concept unknown to the device-repository is removed
+func (this *ConceptRepo) RefreshConcept(id string) (err error) {
+	this.updateMux.Lock()
+	defer this.updateMux.Unlock()
+	defer this.stats.record("concept", id, time.Now(), &err)
+	concept := model.Concept{}
+	found, err := this.loadJSON("/concepts/"+url.PathEscape(id), &concept)
+	if err != nil {
+		return err
+	}
+	if !found {
+		this.update(func(source *Snapshot) {
+			source.Concepts = slices.DeleteFunc(source.Concepts, func(element SnapshotElement) bool {
+				return element.Concept.Id == id
+			})
+		})
+		return nil
+	}
+	element := SnapshotElement{Concept: concept}
+	for _, characteristicId := range concept.CharacteristicIds {
+		characteristic, err := this.loadCharacteristic(characteristicId)
+		if err != nil {
+			return err
+		}
+		element.Characteristics = append(element.Characteristics, characteristic)
+	}
+	this.update(func(source *Snapshot) {
+		index := slices.IndexFunc(source.Concepts, func(e SnapshotElement) bool {
+			return e.Concept.Id == id
+		})
+		if index == -1 {
+			source.Concepts = append(source.Concepts, element)
+		} else {
+			source.Concepts[index] = element
+		}
+	})
+	return nil
+}
+
+// RefreshCharacteristic reloads the characteristic for every concept referencing it;
+// characteristics not referenced by a known concept are ignored until the concept itself is refreshed
+func (this *ConceptRepo) RefreshCharacteristic(id string) (err error) {
+	this.updateMux.Lock()
+	defer this.updateMux.Unlock()
+	defer this.stats.record("characteristic", id, time.Now(), &err)
+	referenced := slices.ContainsFunc(this.index.Load().source.Concepts, func(element SnapshotElement) bool {
+		return slices.Contains(element.Concept.CharacteristicIds, id)
+	})
+	if !referenced {
+		this.config.GetLogger().Debug("ignore refresh of characteristic without known concept", "id", id)
+		return nil
+	}
+	characteristic := model.Characteristic{}
+	found, err := this.loadJSON("/characteristics/"+url.PathEscape(id), &characteristic)
+	if err != nil {
+		return err
+	}
+	this.update(func(source *Snapshot) {
+		for i, element := range source.Concepts {
+			if !slices.Contains(element.Concept.CharacteristicIds, id) {
+				continue
+			}
+			characteristics := slices.Clone(element.Characteristics)
+			index := slices.IndexFunc(characteristics, func(c model.Characteristic) bool {
+				return c.Id == id
+			})
+			switch {
+			case !found && index > -1:
+				characteristics = slices.Delete(characteristics, index, index+1)
+			case found && index > -1:
+				characteristics[index] = characteristic
+			case found:
+				characteristics = append(characteristics, characteristic)
+			}
+			element.Characteristics = characteristics
+			source.Concepts[i] = element
+		}
+	})
+	return nil
+}
+
+// RefreshFunction reloads the function; a function unknown to the device-repository is removed
+func (this *ConceptRepo) RefreshFunction(id string) (err error) {
+	this.updateMux.Lock()
+	defer this.updateMux.Unlock()
+	defer this.stats.record("function", id, time.Now(), &err)
+	function := model.Function{}
+	found, err := this.loadJSON("/functions/"+url.PathEscape(id), &function)
+	if err != nil {
+		return err
+	}
+	this.update(func(source *Snapshot) {
+		source.Functions = slices.DeleteFunc(source.Functions, func(f FunctionInfo) bool {
+			return f.Id == id
+		})
+		if found {
+			source.Functions = append(source.Functions, FunctionInfo{
+				Id:        function.Id,
+				ConceptId: function.ConceptId,
+			})
+		}
+	})
+	return nil
+}
+
+// update applies f on a copy of the current source and replaces the current state with the result;
+// the caller is expected to hold updateMux
+func (this *ConceptRepo) update(f func(source *Snapshot)) {
+	current := this.index.Load()
+	source := Snapshot{
+		LoadedAt:  current.source.LoadedAt,
+		Concepts:  cloneSnapshotElements(current.source.Concepts),
+		Functions: slices.Clone(current.source.Functions),
+	}
+
+	f(&source)
+	this.apply(source, current.fromSnapshotFile)
+
+	err := this.writeSnapshot(source)
+	if err != nil {
+		this.config.GetLogger().Warn("unable to write concept-repo snapshot", "file", this.config.ConceptRepoSnapshotFile, "error", err)
+	}
+}
+
+// cloneSnapshotElements copies the elements including their slices, so that f may change them without touching the published index
+func cloneSnapshotElements(elements []SnapshotElement) []SnapshotElement {
+	result := make([]SnapshotElement, 0, len(elements))
+	for _, element := range elements {
+		element.Concept.CharacteristicIds = slices.Clone(element.Concept.CharacteristicIds)
+		element.Characteristics = slices.Clone(element.Characteristics)
+		result = append(result, element)
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conceptrepo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// Snapshot is the persisted result of a successful Load()
+type Snapshot struct {
+	LoadedAt  time.Time         `json:"loaded_at"`
+	Concepts  []SnapshotElement `json:"concepts"`
+	Functions []FunctionInfo    `json:"functions"`
+}
+
+type SnapshotElement struct {
+	Concept         model.Concept          `json:"concept"`
+	Characteristics []model.Characteristic `json:"characteristics"`
+}
+
+func (this Snapshot) conceptsById() map[string]SnapshotElement {
+	result := map[string]SnapshotElement{}
+	for _, element := range this.Concepts {
+		result[element.Concept.Id] = element
+	}
+	return result
+}
+
+func (this Snapshot) functionsById() map[string]FunctionInfo {
+	result := map[string]FunctionInfo{}
+	for _, f := range this.Functions {
+		result[f.Id] = f
+	}
+	return result
+}
+
+var ErrSnapshotDisabled = errors.New("concept-repo snapshot file not configured")
+
+func (this *ConceptRepo) snapshotFileEnabled() bool {
+	return this.config.ConceptRepoSnapshotFile != "" && this.config.ConceptRepoSnapshotFile != "-"
+}
+
+// writeSnapshot writes to a temp file in the target directory and renames it, so that a crash never leaves a partial snapshot
+func (this *ConceptRepo) writeSnapshot(snapshot Snapshot) error {
+	if !this.snapshotFileEnabled() {
+		return nil
+	}
+	location := this.config.ConceptRepoSnapshotFile
+	temp, err := os.CreateTemp(filepath.Dir(location), filepath.Base(location)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	err = json.NewEncoder(temp).Encode(snapshot)
+	if err != nil {
+		temp.Close()
+		return err
+	}
+	err = temp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), location)
+}
+
+func (this *ConceptRepo) readSnapshot() (snapshot Snapshot, err error) {
+	if !this.snapshotFileEnabled() {
+		return snapshot, ErrSnapshotDisabled
+	}
+	file, err := os.Open(this.config.ConceptRepoSnapshotFile)
+	if err != nil {
+		return snapshot, err
+	}
+	defer file.Close()
+	err = json.NewDecoder(file).Decode(&snapshot)
+	return snapshot, err
+}
+
+// LoadSnapshot replaces the current state with the content of the configured snapshot file
+func (this *ConceptRepo) LoadSnapshot() error {
+	snapshot, err := this.readSnapshot()
+	if err != nil {
+		return err
+	}
+	this.updateMux.Lock()
+	defer this.updateMux.Unlock()
+	this.apply(snapshot, true)
+	return nil
+}
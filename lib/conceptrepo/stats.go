@@ -0,0 +1,80 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conceptrepo
+
+import (
+	"sync"
+	"time"
+)
+
+const maxRefreshErrors = 20
+
+type Stats struct {
+	FullLoads           int64          `json:"full_loads"`
+	FullLoadFailures    int64          `json:"full_load_failures"`
+	LastFullLoadAt      time.Time      `json:"last_full_load_at"`
+	LastFullLoadSeconds float64        `json:"last_full_load_seconds"`
+	Refreshes           int64          `json:"refreshes"` //incremental
+	RefreshFailures     int64          `json:"refresh_failures"`
+	LastErrors          []RefreshError `json:"last_errors"` //newest first, limited to the last 20
+}
+
+type RefreshError struct {
+	Time  time.Time `json:"time"`
+	Kind  string    `json:"kind"` //full, concept, characteristic or function
+	Id    string    `json:"id,omitempty"`
+	Error string    `json:"error"`
+}
+
+type stats struct {
+	Stats
+	mux sync.Mutex
+}
+
+func (this *stats) record(kind string, id string, start time.Time, err *error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	failed := *err != nil
+	if kind == "full" {
+		this.FullLoads++
+		this.LastFullLoadAt = start
+		this.LastFullLoadSeconds = time.Since(start).Seconds()
+		if failed {
+			this.FullLoadFailures++
+		}
+	} else {
+		this.Refreshes++
+		if failed {
+			this.RefreshFailures++
+		}
+	}
+	if failed {
+		this.LastErrors = append([]RefreshError{{Time: start, Kind: kind, Id: id, Error: (*err).Error()}}, this.LastErrors...)
+		if len(this.LastErrors) > maxRefreshErrors {
+			this.LastErrors = this.LastErrors[:maxRefreshErrors]
+		}
+	}
+}
+
+// Stats returns counters of full loads and incremental refreshes with the most recent errors
+func (this *ConceptRepo) Stats() Stats {
+	this.stats.mux.Lock()
+	defer this.stats.mux.Unlock()
+	result := this.stats.Stats
+	result.LastErrors = append([]RefreshError{}, this.stats.LastErrors...)
+	return result
+}
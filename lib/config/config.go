@@ -41,12 +41,47 @@ type Config struct {
 	AuthClientSecret             string   `json:"auth_client_secret"`
 	DeviceRepositoryUrl          string   `json:"device_repository_url"`
 	ConceptRepoRefreshInterval   int64    `json:"concept_repo_refresh_interval"`
+	ConceptRepoSnapshotFile      string   `json:"concept_repo_snapshot_file"`  //optional, last successful concept-repo load; used if the device-repository is unavailable on startup
+	ConceptRepoRetryInterval     int64    `json:"concept_repo_retry_interval"` //seconds between load retries while running from the snapshot file
 	ConverterUrl                 string   `json:"converter_url"`
 	ReturnUnknownPathAsNull      bool     `json:"return_unknown_path_as_null"`
+	ValidateMarshallingInput     bool     `json:"validate_marshalling_input"` //default for /v2/marshal requests without validate field
 	Debug                        bool     `json:"debug"`
 	KafkaUrl                     string   `json:"kafka_url"`                       //optional, used for cache invalidation
 	CacheInvalidationKafkaTopics []string `json:"cache_invalidation_kafka_topics"` //optional, used for cache invalidation
 	InitTopics                   bool     `json:"init_topics"`
+	HealthCheckTimeout           int64    `json:"health_check_timeout"` //milliseconds per /health/ready dependency check
+
+	OutputValidationPolicy          string            `json:"output_validation_policy"`           //policy for invalid /v2/unmarshal values: "", "flag", "null" or "reject"
+	OutputValidationServicePolicies map[string]string `json:"output_validation_service_policies"` //service id to policy; overrides OutputValidationPolicy
+
+	AuthTokenProvider  string `json:"auth_token_provider"`   //provider of outbound tokens: "oidc" (default), "static", "file" or "mtls"
+	AuthRealm          string `json:"auth_realm"`            //oidc realm; default "master"
+	AuthTokenUrl       string `json:"auth_token_url"`        //oidc token url; overrides AuthEndpoint and AuthRealm
+	AuthStaticToken    string `json:"auth_static_token"`     //used by the "static" provider
+	AuthTokenFile      string `json:"auth_token_file"`       //used by the "file" provider
+	AuthClientCertFile string `json:"auth_client_cert_file"` //optional client certificate of the "mtls" provider
+	AuthClientKeyFile  string `json:"auth_client_key_file"`  //optional client key of the "mtls" provider
+	AuthCaFile         string `json:"auth_ca_file"`          //optional ca of the "mtls" provider
+
+	AuthJwksUrl          string            `json:"auth_jwks_url"`           //optional, enables token verification and the admin endpoints; http(s) url or local file
+	AuthPublicPaths      []string          `json:"auth_public_paths"`       //path prefixes usable without token if AuthJwksUrl is set
+	AuthEndpointRoles    map[string]string `json:"auth_endpoint_roles"`     //path prefix to roles separated by "|"; the longest matching prefix is used
+	AuthForwardUserToken bool              `json:"auth_forward_user_token"` //use the callers token instead of the service account to load services, device-types and protocols
+
+	CorsAllowedOrigins   []string `json:"cors_allowed_origins"`   //exact origins, patterns like "https://*.example.com" or "*"; empty allows no cross-origin requests
+	CorsAllowedHeaders   []string `json:"cors_allowed_headers"`   //empty uses util.DefaultCorsAllowedHeaders
+	CorsAllowedMethods   []string `json:"cors_allowed_methods"`   //empty uses util.DefaultCorsAllowedMethods
+	CorsExposedHeaders   []string `json:"cors_exposed_headers"`   //response headers readable by browsers
+	CorsMaxAge           int64    `json:"cors_max_age"`           //seconds preflight responses may be cached; 0 omits the header
+	CorsAllowCredentials bool     `json:"cors_allow_credentials"` //only used for origins matched exactly or by pattern
+
+	MetricsLabels                    []string `json:"metrics_labels"`                       //labels of the (un)marshalling request histograms: "call_source", "endpoint", "service_id" and/or "function_ids"; empty uses all
+	MetricsServiceIdAllowList        []string `json:"metrics_service_id_allow_list"`        //if set, other service ids are reported as "other"
+	MetricsFunctionIdAllowList       []string `json:"metrics_function_id_allow_list"`       //if set, other function ids are reported as "other"
+	MetricsMaxLabelValues            int64    `json:"metrics_max_label_values"`             //distinct values per call_source, service_id and function_ids label; later values are reported as "other"; 0 is unlimited
+	MetricsCallSourceCacheSize       int64    `json:"metrics_call_source_cache_size"`       //remote hosts with cached reverse dns lookup; default 1000
+	MetricsCallSourceCacheExpiration int64    `json:"metrics_call_source_cache_expiration"` //seconds; default 3600
 
 	LogLevel string       `json:"log_level"`
 	logger   *slog.Logger `json:"-"`
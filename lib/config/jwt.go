@@ -25,6 +25,7 @@ import (
 	"log/slog"
 	"net/http"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"net/url"
@@ -33,16 +34,17 @@ import (
 type Impersonate string
 
 func (this Impersonate) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	return this.PostWithClient(&http.Client{Timeout: 10 * time.Second}, url, contentType, body)
+}
+
+func (this Impersonate) PostWithClient(client *http.Client, url string, contentType string, body io.Reader) (resp *http.Response, err error) {
 	req, err := http.NewRequest("POST", url, body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", string(this))
+	this.SetAuthorization(req)
 	req.Header.Set("Content-Type", contentType)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
 	resp, err = client.Do(req)
 	if err == nil && resp.StatusCode == 401 {
 		buf := new(bytes.Buffer)
@@ -54,13 +56,24 @@ func (this Impersonate) Post(url string, contentType string, body io.Reader) (re
 	return
 }
 
+// SetAuthorization sets the Authorization header of req; empty tokens (e.g. with mTLS) are not sent
+func (this Impersonate) SetAuthorization(req *http.Request) {
+	if this != "" {
+		req.Header.Set("Authorization", string(this))
+	}
+}
+
 func (this Impersonate) PostJSON(url string, body interface{}, result interface{}) (err error) {
+	return this.PostJSONWithClient(&http.Client{Timeout: 10 * time.Second}, url, body, result)
+}
+
+func (this Impersonate) PostJSONWithClient(client *http.Client, url string, body interface{}, result interface{}) (err error) {
 	b := new(bytes.Buffer)
 	err = json.NewEncoder(b).Encode(body)
 	if err != nil {
 		return err
 	}
-	resp, err := this.Post(url, "application/json", b)
+	resp, err := this.PostWithClient(client, url, "application/json", b)
 	if err != nil {
 		return err
 	}
@@ -77,14 +90,15 @@ func (this Impersonate) PostJSON(url string, body interface{}, result interface{
 }
 
 func (this Impersonate) Get(url string) (resp *http.Response, err error) {
+	return this.GetWithClient(&http.Client{Timeout: 10 * time.Second}, url)
+}
+
+func (this Impersonate) GetWithClient(client *http.Client, url string) (resp *http.Response, err error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", string(this))
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	this.SetAuthorization(req)
 	resp, err = client.Do(req)
 	if err == nil && resp.StatusCode == 401 {
 		buf := new(bytes.Buffer)
@@ -96,7 +110,11 @@ func (this Impersonate) Get(url string) (resp *http.Response, err error) {
 }
 
 func (this Impersonate) GetJSON(url string, result interface{}) (err error) {
-	resp, err := this.Get(url)
+	return this.GetJSONWithClient(&http.Client{Timeout: 10 * time.Second}, url, result)
+}
+
+func (this Impersonate) GetJSONWithClient(client *http.Client, url string, result interface{}) (err error) {
+	resp, err := this.GetWithClient(client, url)
 	if err != nil {
 		return err
 	}
@@ -126,9 +144,11 @@ type OpenidToken struct {
 	RequestTime      time.Time `json:"-"`
 }
 
+// Access is the TokenProvider for the OIDC client-credentials flow; Ensure may be used concurrently
 type Access struct {
 	openid *OpenidToken
 	config Config
+	mux    sync.Mutex
 }
 
 func NewAccess(config Config) *Access {
@@ -136,6 +156,8 @@ func NewAccess(config Config) *Access {
 }
 
 func (this *Access) Ensure() (token Impersonate, err error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
 	if this.openid == nil {
 		this.openid = &OpenidToken{}
 	}
@@ -167,9 +189,21 @@ func (this *Access) Ensure() (token Impersonate, err error) {
 	return
 }
 
+// tokenUrl uses config.AuthTokenUrl or the keycloak token endpoint of config.AuthRealm (default "master")
+func tokenUrl(config Config) string {
+	if config.AuthTokenUrl != "" {
+		return config.AuthTokenUrl
+	}
+	realm := config.AuthRealm
+	if realm == "" {
+		realm = "master"
+	}
+	return config.AuthEndpoint + "/auth/realms/" + url.PathEscape(realm) + "/protocol/openid-connect/token"
+}
+
 func getOpenidToken(token *OpenidToken, config Config) (err error) {
 	requesttime := TimeNow()
-	resp, err := http.PostForm(config.AuthEndpoint+"/auth/realms/master/protocol/openid-connect/token", url.Values{
+	resp, err := http.PostForm(tokenUrl(config), url.Values{
 		"client_id":     {config.AuthClientId},
 		"client_secret": {config.AuthClientSecret},
 		"grant_type":    {"client_credentials"},
@@ -193,7 +227,7 @@ func getOpenidToken(token *OpenidToken, config Config) (err error) {
 
 func refreshOpenidToken(token *OpenidToken, config Config) (err error) {
 	requesttime := TimeNow()
-	resp, err := http.PostForm(config.AuthEndpoint+"/auth/realms/master/protocol/openid-connect/token", url.Values{
+	resp, err := http.PostForm(tokenUrl(config), url.Values{
 		"client_id":     {config.AuthClientId},
 		"client_secret": {config.AuthClientSecret},
 		"refresh_token": {token.RefreshToken},
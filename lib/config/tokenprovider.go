@@ -0,0 +1,168 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TokenProviderOidc   = "oidc"   //client-credentials flow against AuthTokenUrl or the AuthRealm of AuthEndpoint
+	TokenProviderStatic = "static" //AuthStaticToken
+	TokenProviderFile   = "file"   //token mounted at AuthTokenFile, reloaded on change
+	TokenProviderMTLS   = "mtls"   //no token; AuthClientCertFile and AuthClientKeyFile are used as client certificate if set
+)
+
+// TokenProvider provides the Authorization header value for outbound service calls
+type TokenProvider interface {
+	Ensure() (Impersonate, error)
+}
+
+// NewTokenProvider selects the TokenProvider by config.AuthTokenProvider (default TokenProviderOidc)
+func NewTokenProvider(config Config) (TokenProvider, error) {
+	switch config.AuthTokenProvider {
+	case "", TokenProviderOidc:
+		return NewAccess(config), nil
+	case TokenProviderStatic:
+		if config.AuthStaticToken == "" {
+			return nil, errors.New("missing auth_static_token")
+		}
+		return StaticToken(bearer(config.AuthStaticToken)), nil
+	case TokenProviderFile:
+		if config.AuthTokenFile == "" {
+			return nil, errors.New("missing auth_token_file")
+		}
+		return &FileToken{Location: config.AuthTokenFile}, nil
+	case TokenProviderMTLS:
+		return NewMTLS(config)
+	}
+	return nil, errors.New("unknown auth_token_provider " + config.AuthTokenProvider)
+}
+
+type StaticToken Impersonate
+
+func (this StaticToken) Ensure() (Impersonate, error) {
+	return Impersonate(this), nil
+}
+
+// FileToken reads the token from Location and rereads it when the modification time or size of the file changes
+type FileToken struct {
+	Location string
+	mux      sync.Mutex
+	token    Impersonate
+	modTime  time.Time
+	size     int64
+}
+
+func (this *FileToken) Ensure() (Impersonate, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	info, err := os.Stat(this.Location)
+	if err != nil {
+		return "", err
+	}
+	if this.token != "" && info.ModTime().Equal(this.modTime) && info.Size() == this.size {
+		return this.token, nil
+	}
+	content, err := os.ReadFile(this.Location)
+	if err != nil {
+		return "", err
+	}
+	token := bearer(string(content))
+	if token == "" {
+		return "", errors.New("empty token file " + this.Location)
+	}
+	this.token = token
+	this.modTime = info.ModTime()
+	this.size = info.Size()
+	return this.token, nil
+}
+
+// MTLS sends no token; the client certificate is used by clients created with HttpClient
+type MTLS struct {
+	transport *http.Transport
+}
+
+func NewMTLS(config Config) (result MTLS, err error) {
+	if config.AuthClientCertFile == "" && config.AuthClientKeyFile == "" && config.AuthCaFile == "" {
+		return result, nil //e.g. if tls is handled by a sidecar
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.AuthClientCertFile != "" || config.AuthClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.AuthClientCertFile, config.AuthClientKeyFile)
+		if err != nil {
+			return result, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if config.AuthCaFile != "" {
+		ca, err := os.ReadFile(config.AuthCaFile)
+		if err != nil {
+			return result, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return result, errors.New("no certificates found in " + config.AuthCaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return result, errors.New("unable to configure client certificate: unexpected http.DefaultTransport")
+	}
+	result.transport = defaultTransport.Clone()
+	result.transport.TLSClientConfig = tlsConfig
+	return result, nil
+}
+
+func (this MTLS) Ensure() (Impersonate, error) {
+	return "", nil
+}
+
+// Transport returns nil if no client certificate or ca is configured
+func (this MTLS) Transport() http.RoundTripper {
+	if this.transport == nil {
+		return nil
+	}
+	return this.transport
+}
+
+// HttpClient returns the client for outbound service calls authorized by access (device-repository and converter);
+// the transport of access is used if it provides one (e.g. MTLS)
+func HttpClient(access TokenProvider) *http.Client {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if provider, ok := access.(interface{ Transport() http.RoundTripper }); ok {
+		client.Transport = provider.Transport()
+	}
+	return client
+}
+
+// bearer adds the Bearer prefix to raw tokens
+func bearer(token string) Impersonate {
+	token = strings.TrimSpace(token)
+	if token == "" || strings.HasPrefix(strings.ToLower(token), "bearer ") {
+		return Impersonate(token)
+	}
+	return Impersonate("Bearer " + token)
+}
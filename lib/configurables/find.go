@@ -17,6 +17,7 @@
 package configurables
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/mapping"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
@@ -27,14 +28,17 @@ type ConceptRepo interface {
 	GetConceptsOfCharacteristic(characteristicId string) (conceptId []string, err error)
 	GetCharacteristic(id string) (model.Characteristic, error)
 	GetConcept(id string) (concept model.Concept, err error)
+	GetConceptIdOfFunction(id string) string
 }
 
 type ConfigurableService struct {
 	conceptrepo ConceptRepo
+	inputPaths  InputPathProvider
 }
 
-func New(repo ConceptRepo) *ConfigurableService {
-	return &ConfigurableService{conceptrepo: repo}
+// New creates a ConfigurableService; inputPaths is only needed by FindForFunction and may be nil
+func New(repo ConceptRepo, inputPaths InputPathProvider) *ConfigurableService {
+	return &ConfigurableService{conceptrepo: repo, inputPaths: inputPaths}
 }
 
 func (this *ConfigurableService) Find(notCharacteristicId string, services []model.Service) (result Configurables, err error) {
@@ -72,6 +76,18 @@ func FindIntersectingConfigurables(repo ConceptRepo, notCharacteristicId string,
 			if err != nil {
 				return nil, err
 			}
+			for _, service := range services {
+				for _, content := range service.Inputs {
+					paths, err := pathsOfConcept(repo, content.ContentVariable, conceptId, []string{})
+					if err != nil {
+						return nil, err
+					}
+					for _, path := range paths {
+						path.ServiceId = service.Id
+						configurable.Paths = append(configurable.Paths, path)
+					}
+				}
+			}
 			result = append(result, configurable)
 		}
 	}
@@ -112,29 +128,11 @@ func createConfigurable(repo ConceptRepo, conceptId string) (result Configurable
 func createConfigurableValues(characteristic model.Characteristic, labelPrefix string, pathSegments ...string) (result []ConfigurableCharacteristicValue) {
 	switch characteristic.Type {
 	case model.Integer, model.Float:
-		return []ConfigurableCharacteristicValue{
-			{
-				Label: strings.Join(append([]string{labelPrefix}, pathSegments...), " "),
-				Path:  strings.Join(pathSegments, "."),
-				Value: "0",
-			},
-		}
+		return []ConfigurableCharacteristicValue{createConfigurableValue(characteristic, "0", labelPrefix, pathSegments)}
 	case model.Boolean:
-		return []ConfigurableCharacteristicValue{
-			{
-				Label: strings.Join(append([]string{labelPrefix}, pathSegments...), " "),
-				Path:  strings.Join(pathSegments, "."),
-				Value: "false",
-			},
-		}
+		return []ConfigurableCharacteristicValue{createConfigurableValue(characteristic, "false", labelPrefix, pathSegments)}
 	case model.String:
-		return []ConfigurableCharacteristicValue{
-			{
-				Label: strings.Join(append([]string{labelPrefix}, pathSegments...), " "),
-				Path:  strings.Join(pathSegments, "."),
-				Value: "",
-			},
-		}
+		return []ConfigurableCharacteristicValue{createConfigurableValue(characteristic, "", labelPrefix, pathSegments)}
 	case model.List:
 		for _, sub := range characteristic.SubCharacteristics {
 			index := "0"
@@ -154,6 +152,50 @@ func createConfigurableValues(characteristic model.Characteristic, labelPrefix s
 	}
 }
 
+// createConfigurableValue uses the characteristic value as default and falls back to fallback if the characteristic has none
+func createConfigurableValue(characteristic model.Characteristic, fallback string, labelPrefix string, pathSegments []string) ConfigurableCharacteristicValue {
+	value := fallback
+	if characteristic.Value != nil {
+		temp, err := json.Marshal(characteristic.Value)
+		if err == nil {
+			value = string(temp)
+		}
+	}
+	return ConfigurableCharacteristicValue{
+		Label:            strings.Join(append([]string{labelPrefix}, pathSegments...), " "),
+		Path:             strings.Join(pathSegments, "."),
+		Value:            value,
+		CharacteristicId: characteristic.Id,
+		Type:             characteristic.Type,
+		DisplayUnit:      characteristic.DisplayUnit,
+		MinValue:         characteristic.MinValue,
+		MaxValue:         characteristic.MaxValue,
+		AllowedValues:    characteristic.AllowedValues,
+	}
+}
+
+// pathsOfConcept returns the v2 paths of the outermost variables with a characteristic of the concept
+func pathsOfConcept(repo ConceptRepo, variable model.ContentVariable, conceptId string, currentPath []string) (result []ConfigurablePath, err error) {
+	currentPath = append(currentPath, variable.Name)
+	if variable.CharacteristicId != "" {
+		concepts, err := repo.GetConceptsOfCharacteristic(variable.CharacteristicId)
+		if err != nil {
+			return nil, err
+		}
+		if contains(concepts, conceptId) {
+			return []ConfigurablePath{{Path: strings.Join(currentPath, "."), CharacteristicId: variable.CharacteristicId}}, nil
+		}
+	}
+	for _, sub := range variable.SubContentVariables {
+		subResult, err := pathsOfConcept(repo, sub, conceptId, currentPath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, subResult...)
+	}
+	return result, nil
+}
+
 func characteristicsInContentVariable(variable model.ContentVariable) (result []string) {
 	if variable.CharacteristicId != "" {
 		result = append(result, variable.CharacteristicId)
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configurables
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// InputPathProvider is implemented by the v2 marshaller; configurables may not import it directly
+type InputPathProvider interface {
+	GetInputPaths(service model.Service, functionId string, aspectNode *model.AspectNode) (result []string)
+}
+
+// FindForFunction lists, per device type and service, the input variables that may be set together with a command of functionId.
+// services without an input path for functionId and aspectNode are ignored, as are device types without such services.
+func (this *ConfigurableService) FindForFunction(functionId string, aspectNode *model.AspectNode, deviceTypes []model.DeviceType) (result []DeviceTypeConfigurables, err error) {
+	if this.inputPaths == nil {
+		return nil, errors.New("function configurables need an input path provider")
+	}
+	result = []DeviceTypeConfigurables{}
+	for _, deviceType := range deviceTypes {
+		element := DeviceTypeConfigurables{DeviceTypeId: deviceType.Id, Services: []ServiceConfigurables{}}
+		for _, service := range deviceType.Services {
+			functionPaths := this.inputPaths.GetInputPaths(service, functionId, aspectNode)
+			if len(functionPaths) == 0 {
+				continue
+			}
+			serviceConfigurables := ServiceConfigurables{
+				ServiceId:     service.Id,
+				FunctionPaths: functionPaths,
+				Configurables: []FunctionConfigurable{},
+			}
+			for _, content := range service.Inputs {
+				list, err := this.functionConfigurables(content.ContentVariable, functionPaths, []string{})
+				if err != nil {
+					return nil, err
+				}
+				serviceConfigurables.Configurables = append(serviceConfigurables.Configurables, list...)
+			}
+			element.Services = append(element.Services, serviceConfigurables)
+		}
+		if len(element.Services) > 0 {
+			result = append(result, element)
+		}
+	}
+	return result, nil
+}
+
+// functionConfigurables returns the outermost variables with a function or characteristic that are not part of functionPaths
+func (this *ConfigurableService) functionConfigurables(variable model.ContentVariable, functionPaths []string, currentPath []string) (result []FunctionConfigurable, err error) {
+	currentPath = append(currentPath, variable.Name)
+	path := strings.Join(currentPath, ".")
+	for _, functionPath := range functionPaths {
+		if path == functionPath || strings.HasPrefix(path, functionPath+".") {
+			return nil, nil
+		}
+	}
+	if variable.FunctionId != "" || variable.CharacteristicId != "" {
+		element := FunctionConfigurable{
+			Path:             path,
+			FunctionId:       variable.FunctionId,
+			AspectId:         variable.AspectId,
+			CharacteristicId: variable.CharacteristicId,
+		}
+		if variable.FunctionId != "" {
+			element.ConceptId = this.conceptrepo.GetConceptIdOfFunction(variable.FunctionId)
+		}
+		if element.ConceptId == "" && variable.CharacteristicId != "" {
+			conceptIds, err := this.conceptrepo.GetConceptsOfCharacteristic(variable.CharacteristicId)
+			if err != nil {
+				return nil, err
+			}
+			if len(conceptIds) > 0 {
+				element.ConceptId = conceptIds[0]
+			}
+		}
+		if variable.CharacteristicId != "" {
+			characteristic, err := this.conceptrepo.GetCharacteristic(variable.CharacteristicId)
+			if err != nil {
+				return nil, err
+			}
+			element.Values = createConfigurableValues(characteristic, characteristic.Name)
+		}
+		return []FunctionConfigurable{element}, nil
+	}
+	for _, sub := range variable.SubContentVariables {
+		subResult, err := this.functionConfigurables(sub, functionPaths, currentPath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, subResult...)
+	}
+	return result, nil
+}
@@ -16,15 +16,53 @@
 
 package configurables
 
+import "github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+
 type Configurable struct {
 	CharacteristicId string                            `json:"characteristic_id"`
 	Values           []ConfigurableCharacteristicValue `json:"values"`
+	Paths            []ConfigurablePath                `json:"paths,omitempty"` //v2 input paths the configurable applies to; informational, v2.Marshaller.Marshal finds them itself
+}
+
+type ConfigurablePath struct {
+	ServiceId        string `json:"service_id"`
+	Path             string `json:"path"`
+	CharacteristicId string `json:"characteristic_id"`
 }
 
 type ConfigurableCharacteristicValue struct {
 	Label string `json:"label"`
 	Path  string `json:"path"`
-	Value string `json:"value"`
+	Value string `json:"value"` //json encoded; default value of the characteristic if known
+
+	//metadata of the characteristic at Path, to render and validate user input
+	CharacteristicId string        `json:"characteristic_id,omitempty"`
+	Type             model.Type    `json:"type,omitempty"`
+	DisplayUnit      string        `json:"display_unit,omitempty"`
+	MinValue         interface{}   `json:"min_value,omitempty"`
+	MaxValue         interface{}   `json:"max_value,omitempty"`
+	AllowedValues    []interface{} `json:"allowed_values,omitempty"`
 }
 
 type Configurables []Configurable
+
+type DeviceTypeConfigurables struct {
+	DeviceTypeId string                 `json:"device_type_id"`
+	Services     []ServiceConfigurables `json:"services"`
+}
+
+type ServiceConfigurables struct {
+	ServiceId     string                 `json:"service_id"`
+	FunctionPaths []string               `json:"function_paths"` //input paths of the requested function
+	Configurables []FunctionConfigurable `json:"configurables"`
+}
+
+// FunctionConfigurable is an input variable which may be set by a v2 marshalling request in addition to the requested function
+type FunctionConfigurable struct {
+	Path             string                            `json:"path"`
+	FunctionId       string                            `json:"function_id,omitempty"`
+	AspectId         string                            `json:"aspect_id,omitempty"`
+	ConceptId        string                            `json:"concept_id,omitempty"`
+	CharacteristicId string                            `json:"characteristic_id,omitempty"`
+	Values           []ConfigurableCharacteristicValue `json:"values,omitempty"`
+}
@@ -20,27 +20,50 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	"github.com/SENERGY-Platform/models/go/models"
+	"net/http"
 	"net/url"
+	"time"
 )
 
 type Converter struct {
-	config config.Config
-	access *config.Access
+	config  config.Config
+	access  config.TokenProvider
+	client  *http.Client
+	metrics Metrics
 }
 
-func New(config config.Config, access *config.Access) *Converter {
-	return &Converter{config: config, access: access}
+type Metrics interface {
+	LogConverterCall(from string, to string, duration time.Duration, err error)
+}
+
+func New(conf config.Config, access config.TokenProvider) *Converter {
+	return &Converter{config: conf, access: access, client: config.HttpClient(access)}
+}
+
+// SetMetrics enables the logging of the duration of conversion calls
+func (this *Converter) SetMetrics(metrics Metrics) {
+	this.metrics = metrics
+}
+
+func (this *Converter) logCall(from string, to string, start time.Time, err error) {
+	if this.metrics != nil {
+		this.metrics.LogConverterCall(from, to, time.Since(start), err)
+	}
 }
 
 func (this *Converter) Cast(in interface{}, from marshaller.CharacteristicId, to marshaller.CharacteristicId) (out interface{}, err error) {
 	if from == to {
 		return in, nil
 	}
+	start := time.Now()
+	defer func() {
+		this.logCall(from, to, start, err)
+	}()
 	token, err := this.access.Ensure()
 	if err != nil {
 		return out, err
 	}
-	err = token.PostJSON(this.config.ConverterUrl+"/conversions/"+url.PathEscape(from)+"/"+url.PathEscape(to), in, &out)
+	err = token.PostJSONWithClient(this.client, this.config.ConverterUrl+"/conversions/"+url.PathEscape(from)+"/"+url.PathEscape(to), in, &out)
 	return out, err
 }
 
@@ -48,11 +71,15 @@ func (this *Converter) CastWithExtension(in interface{}, from marshaller.Charact
 	if from == to {
 		return in, nil
 	}
+	start := time.Now()
+	defer func() {
+		this.logCall(from, to, start, err)
+	}()
 	token, err := this.access.Ensure()
 	if err != nil {
 		return out, err
 	}
-	err = token.PostJSON(this.config.ConverterUrl+"/extended-conversions/"+url.PathEscape(from)+"/"+url.PathEscape(to), map[string]interface{}{
+	err = token.PostJSONWithClient(this.client, this.config.ConverterUrl+"/extended-conversions/"+url.PathEscape(from)+"/"+url.PathEscape(to), map[string]interface{}{
 		"input":      in,
 		"extensions": extensions,
 	}, &out)
@@ -64,7 +91,7 @@ func (this *Converter) TryExtension(call ExtensionCall) (resp ExtensionCallRespo
 	if err != nil {
 		return resp, err
 	}
-	err = token.PostJSON(this.config.ConverterUrl+"/extension-call", call, &resp)
+	err = token.PostJSONWithClient(this.client, this.config.ConverterUrl+"/extension-call", call, &resp)
 	return resp, err
 }
 
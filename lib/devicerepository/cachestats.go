@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devicerepository
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/SENERGY-Platform/service-commons/pkg/cache"
+)
+
+const (
+	CacheKindService    = "service"
+	CacheKindDeviceType = "device-type"
+	CacheKindProtocol   = "protocol"
+	CacheKindDevice     = "device"
+	CacheKindAspectNode = "aspect-node"
+)
+
+var CacheKinds = []string{CacheKindService, CacheKindDeviceType, CacheKindProtocol, CacheKindDevice, CacheKindAspectNode}
+
+type CacheStats struct {
+	Hits   int64
+	Misses int64 //includes failed loads
+}
+
+type cacheCounter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// CacheStats returns the cache hits and misses by kind (see CacheKinds) since the start;
+// requests with tokens that are not cached are not counted
+func (this *DeviceRepository) CacheStats() map[string]CacheStats {
+	result := map[string]CacheStats{}
+	for kind, counter := range this.cacheCounters {
+		result[kind] = CacheStats{Hits: counter.hits.Load(), Misses: counter.misses.Load()}
+	}
+	return result
+}
+
+func newCacheCounters() map[string]*cacheCounter {
+	result := map[string]*cacheCounter{}
+	for _, kind := range CacheKinds {
+		result[kind] = &cacheCounter{}
+	}
+	return result
+}
+
+// useCache is cache.Use counting hits and misses of kind
+func useCache[T any](this *DeviceRepository, kind string, key string, get func() (T, error), validate func(T) error, exp time.Duration) (result T, err error) {
+	missed := false
+	result, err = cache.Use(this.cache, key, func() (T, error) {
+		missed = true
+		return get()
+	}, validate, exp)
+	if counter, ok := this.cacheCounters[kind]; ok {
+		if missed {
+			counter.misses.Add(1)
+		} else {
+			counter.hits.Add(1)
+		}
+	}
+	return result, err
+}
@@ -18,8 +18,12 @@ package devicerepository
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/marshaller/lib/auth"
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	"github.com/SENERGY-Platform/service-commons/pkg/cache"
@@ -27,16 +31,26 @@ import (
 	"net/http"
 	"net/url"
 	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 type DeviceRepository struct {
-	cache   *cache.Cache
-	repoUrl string
-	access  *config.Access
+	cache         *cache.Cache
+	cacheCounters map[string]*cacheCounter
+	repoUrl       string
+	access        config.TokenProvider
+	client        *http.Client
+
+	//increased by device invalidation signals; part of the keys of scoped device entries, which can not be reached by the cache invalidation hook
+	deviceGenerations    map[string]int64
+	deviceGenerationsMux sync.Mutex
 }
 
-func New(config config.Config, access *config.Access) (*DeviceRepository, error) {
+func New(conf config.Config, access config.TokenProvider) (*DeviceRepository, error) {
 	c, err := cache.New(cache.Config{
 		CacheInvalidationSignalHooks: map[cache.Signal]cache.ToKey{
 			signal.Known.CacheInvalidationAll:        nil,
@@ -44,23 +58,51 @@ func New(config config.Config, access *config.Access) (*DeviceRepository, error)
 			signal.Known.AspectCacheInvalidation: func(signalValue string) (cacheKey string) {
 				return "aspect-nodes." + signalValue
 			},
+			signal.Known.DeviceCacheInvalidation: func(signalValue string) (cacheKey string) {
+				return "device." + signalValue
+			},
 		},
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &DeviceRepository{repoUrl: config.DeviceRepositoryUrl, cache: c, access: access}, nil
+	result := &DeviceRepository{repoUrl: conf.DeviceRepositoryUrl, cache: c, cacheCounters: newCacheCounters(), access: access, client: config.HttpClient(access), deviceGenerations: map[string]int64{}}
+	signal.Known.DeviceCacheInvalidation.Sub(fmt.Sprintf("device-repository-scoped-devices-%p", result), func(id string, _ *sync.WaitGroup) {
+		result.deviceGenerationsMux.Lock()
+		defer result.deviceGenerationsMux.Unlock()
+		result.deviceGenerations[id]++
+	})
+	return result, nil
+}
+
+func (this *DeviceRepository) deviceGeneration(id string) int64 {
+	this.deviceGenerationsMux.Lock()
+	defer this.deviceGenerationsMux.Unlock()
+	return this.deviceGenerations[id]
 }
 
 func (this *DeviceRepository) GetProtocol(id string) (result model.Protocol, err error) {
-	return cache.Use(this.cache, "protocol."+id, func() (model.Protocol, error) {
+	return useCache(this, CacheKindProtocol, "protocol."+id, func() (model.Protocol, error) {
 		return this.getProtocol(id)
-	}, func(protocol model.Protocol) error {
-		if protocol.Id == "" {
-			return errors.New("invalid protocol loaded from cache")
-		}
-		return nil
-	}, time.Minute)
+	}, validProtocol, time.Minute)
+}
+
+// GetProtocolWithToken loads the protocol with the callers token; the result is cached per permission scope
+func (this *DeviceRepository) GetProtocolWithToken(token string, id string) (result model.Protocol, err error) {
+	scope, ok := permissionScope(token)
+	if !ok {
+		return this.getProtocolWithToken(config.Impersonate(token), id)
+	}
+	return useCache(this, CacheKindProtocol, scope+".protocol."+id, func() (model.Protocol, error) {
+		return this.getProtocolWithToken(config.Impersonate(token), id)
+	}, validProtocol, time.Minute)
+}
+
+func validProtocol(protocol model.Protocol) error {
+	if protocol.Id == "" {
+		return errors.New("invalid protocol loaded from cache")
+	}
+	return nil
 }
 
 func (this *DeviceRepository) getProtocol(id string) (result model.Protocol, err error) {
@@ -68,38 +110,60 @@ func (this *DeviceRepository) getProtocol(id string) (result model.Protocol, err
 	if err != nil {
 		return result, err
 	}
-	err = token.GetJSON(this.repoUrl+"/protocols/"+url.QueryEscape(id), &result)
+	return this.getProtocolWithToken(token, id)
+}
+
+func (this *DeviceRepository) getProtocolWithToken(token config.Impersonate, id string) (result model.Protocol, err error) {
+	err = token.GetJSONWithClient(this.client, this.repoUrl+"/protocols/"+url.QueryEscape(id), &result)
 	return
 }
 
 func (this *DeviceRepository) GetDeviceType(id string) (result model.DeviceType, err error, code int) {
 	code = http.StatusOK
-	result, err = cache.Use(this.cache, "device-type."+id, func() (dt model.DeviceType, terr error) {
+	result, err = useCache(this, CacheKindDeviceType, "device-type."+id, func() (dt model.DeviceType, terr error) {
 		dt, terr, code = this.getDeviceType(id)
 		return dt, terr
-	}, func(deviceType model.DeviceType) error {
-		if deviceType.Id == "" {
-			return errors.New("invalid device-type loaded from cache")
-		}
-		return nil
-	}, time.Minute)
+	}, validDeviceType, time.Minute)
+	return result, err, code
+}
+
+// GetDeviceTypeWithToken loads the device-type with the callers token; the result is cached per permission scope
+func (this *DeviceRepository) GetDeviceTypeWithToken(token string, id string) (result model.DeviceType, err error, code int) {
+	scope, ok := permissionScope(token)
+	if !ok {
+		return this.getDeviceTypeWithToken(config.Impersonate(token), id)
+	}
+	code = http.StatusOK
+	result, err = useCache(this, CacheKindDeviceType, scope+".device-type."+id, func() (dt model.DeviceType, terr error) {
+		dt, terr, code = this.getDeviceTypeWithToken(config.Impersonate(token), id)
+		return dt, terr
+	}, validDeviceType, time.Minute)
 	return result, err, code
 }
 
+func validDeviceType(deviceType model.DeviceType) error {
+	if deviceType.Id == "" {
+		return errors.New("invalid device-type loaded from cache")
+	}
+	return nil
+}
+
 func (this *DeviceRepository) getDeviceType(id string) (result model.DeviceType, err error, code int) {
 	token, err := this.access.Ensure()
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
+	return this.getDeviceTypeWithToken(token, id)
+}
+
+func (this *DeviceRepository) getDeviceTypeWithToken(token config.Impersonate, id string) (result model.DeviceType, err error, code int) {
+	code = http.StatusOK
 	req, err := http.NewRequest("GET", this.repoUrl+"/device-types/"+url.PathEscape(id), nil)
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
-	req.Header.Set("Authorization", string(token))
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-	resp, err := client.Do(req)
+	token.SetAuthorization(req)
+	resp, err := this.client.Do(req)
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
@@ -116,6 +180,69 @@ func (this *DeviceRepository) getDeviceType(id string) (result model.DeviceType,
 	return
 }
 
+func (this *DeviceRepository) GetDevice(id string) (result model.Device, err error, code int) {
+	code = http.StatusOK
+	result, err = useCache(this, CacheKindDevice, "device."+id, func() (device model.Device, terr error) {
+		device, terr, code = this.getDevice(id)
+		return device, terr
+	}, validDevice, time.Minute)
+	return result, err, code
+}
+
+// GetDeviceWithToken loads the device with the callers token; the result is cached per permission scope
+func (this *DeviceRepository) GetDeviceWithToken(token string, id string) (result model.Device, err error, code int) {
+	scope, ok := permissionScope(token)
+	if !ok {
+		return this.getDeviceWithToken(config.Impersonate(token), id)
+	}
+	code = http.StatusOK
+	result, err = useCache(this, CacheKindDevice, scope+".device."+id+"."+strconv.FormatInt(this.deviceGeneration(id), 10), func() (device model.Device, terr error) {
+		device, terr, code = this.getDeviceWithToken(config.Impersonate(token), id)
+		return device, terr
+	}, validDevice, time.Minute)
+	return result, err, code
+}
+
+func validDevice(device model.Device) error {
+	if device.Id == "" {
+		return errors.New("invalid device loaded from cache")
+	}
+	return nil
+}
+
+func (this *DeviceRepository) getDevice(id string) (result model.Device, err error, code int) {
+	token, err := this.access.Ensure()
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	return this.getDeviceWithToken(token, id)
+}
+
+func (this *DeviceRepository) getDeviceWithToken(token config.Impersonate, id string) (result model.Device, err error, code int) {
+	code = http.StatusOK
+	req, err := http.NewRequest("GET", this.repoUrl+"/devices/"+url.PathEscape(id), nil)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	token.SetAuthorization(req)
+	resp, err := this.client.Do(req)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return result, errors.New("device not found"), resp.StatusCode
+	}
+	if resp.StatusCode >= 300 {
+		return result, errors.New("unexpected status code"), resp.StatusCode
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	return
+}
+
 func (this *DeviceRepository) GetService(id string) (result model.Service, err error) {
 	result, err, _ = this.GetServiceWithErrCode(id)
 	return
@@ -123,32 +250,50 @@ func (this *DeviceRepository) GetService(id string) (result model.Service, err e
 
 func (this *DeviceRepository) GetServiceWithErrCode(id string) (result model.Service, err error, code int) {
 	code = http.StatusOK
-	result, err = cache.Use(this.cache, "service."+id, func() (service model.Service, terr error) {
+	result, err = useCache(this, CacheKindService, "service."+id, func() (service model.Service, terr error) {
 		service, terr, code = this.getServiceWithErrCode(id)
 		return service, terr
-	}, func(service model.Service) error {
-		if service.Id == "" {
-			return errors.New("invalid service loaded from cache")
-		}
-		return nil
-	}, time.Minute)
+	}, validService, time.Minute)
+	return result, err, code
+}
+
+// GetServiceWithToken loads the service with the callers token; the result is cached per permission scope
+func (this *DeviceRepository) GetServiceWithToken(token string, id string) (result model.Service, err error, code int) {
+	scope, ok := permissionScope(token)
+	if !ok {
+		return this.getServiceWithToken(config.Impersonate(token), id)
+	}
+	code = http.StatusOK
+	result, err = useCache(this, CacheKindService, scope+".service."+id, func() (service model.Service, terr error) {
+		service, terr, code = this.getServiceWithToken(config.Impersonate(token), id)
+		return service, terr
+	}, validService, time.Minute)
 	return result, err, code
 }
 
+func validService(service model.Service) error {
+	if service.Id == "" {
+		return errors.New("invalid service loaded from cache")
+	}
+	return nil
+}
+
 func (this *DeviceRepository) getServiceWithErrCode(id string) (result model.Service, err error, code int) {
 	token, err := this.access.Ensure()
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
+	return this.getServiceWithToken(token, id)
+}
+
+func (this *DeviceRepository) getServiceWithToken(token config.Impersonate, id string) (result model.Service, err error, code int) {
+	code = http.StatusOK
 	req, err := http.NewRequest("GET", this.repoUrl+"/services/"+url.PathEscape(id), nil)
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
-	req.Header.Set("Authorization", string(token))
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-	resp, err := client.Do(req)
+	token.SetAuthorization(req)
+	resp, err := this.client.Do(req)
 	if err != nil {
 		return result, err, http.StatusInternalServerError
 	}
@@ -166,7 +311,7 @@ func (this *DeviceRepository) getServiceWithErrCode(id string) (result model.Ser
 }
 
 func (this *DeviceRepository) GetAspectNode(id string) (result model.AspectNode, err error) {
-	result, err = cache.Use(this.cache, "aspect-nodes."+id, func() (aspect model.AspectNode, terr error) {
+	result, err = useCache(this, CacheKindAspectNode, "aspect-nodes."+id, func() (aspect model.AspectNode, terr error) {
 		token, err := this.access.Ensure()
 		if err != nil {
 			return aspect, err
@@ -176,8 +321,8 @@ func (this *DeviceRepository) GetAspectNode(id string) (result model.AspectNode,
 			debug.PrintStack()
 			return aspect, err
 		}
-		req.Header.Set("Authorization", string(token))
-		resp, err := http.DefaultClient.Do(req)
+		token.SetAuthorization(req)
+		resp, err := this.client.Do(req)
 		if err != nil {
 			debug.PrintStack()
 			return aspect, err
@@ -199,3 +344,16 @@ func (this *DeviceRepository) GetAspectNode(id string) (result model.AspectNode,
 	}, time.Minute)
 	return result, err
 }
+
+// permissionScope partitions cached results of user requests by subject and roles of the token.
+// Tokens without subject are not cached.
+func permissionScope(token string) (scope string, ok bool) {
+	parsed, err := auth.Parse(token)
+	if err != nil || parsed.Sub == "" {
+		return "", false
+	}
+	roles := slices.Clone(parsed.RealmAccess.Roles)
+	slices.Sort(roles)
+	hash := sha256.Sum256([]byte(parsed.Sub + "\n" + strings.Join(roles, ",")))
+	return "scope." + hex.EncodeToString(hash[:]), true
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+// Reachable checks that the service at url answers http requests without a server error;
+// the response content is ignored, so no credentials are needed.
+// client should be the one used to access the service (e.g. config.HttpClient) to check the same transport (tls, proxies)
+func Reachable(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		if url == "" || url == "-" {
+			return ErrDisabled
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("unexpected response status %v", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+type Access interface {
+	Ensure() (config.Impersonate, error)
+}
+
+// Token checks that access is able to provide a (possibly cached) token
+func Token(conf config.Config, access Access) Check {
+	return func(ctx context.Context) error {
+		switch conf.AuthTokenProvider {
+		case config.TokenProviderMTLS:
+			return ErrDisabled
+		case "", config.TokenProviderOidc:
+			if (conf.AuthEndpoint == "" || conf.AuthEndpoint == "-") && conf.AuthTokenUrl == "" {
+				return ErrDisabled
+			}
+		}
+		token, err := access.Ensure()
+		if err != nil {
+			return err
+		}
+		if token == "" || token == "Bearer " {
+			return errors.New("missing token")
+		}
+		return nil
+	}
+}
+
+// State is a Check for components which report their state themselves (e.g. background workers)
+type State struct {
+	err error
+	mux sync.Mutex
+
+	lastError     error
+	lastErrorTime time.Time
+}
+
+// NewState returns a State reporting initial, which may be ErrDisabled or nil
+func NewState(initial error) *State {
+	return &State{err: initial}
+}
+
+func (this *State) Set(err error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.err = err
+}
+
+// ErrorExpiration is the duration a State reports errors of ReportError
+const ErrorExpiration = 2 * time.Minute
+
+// ReportError is used for temporary errors of components which do not report their recovery;
+// the error is reported with its time until ErrorExpiration passed
+func (this *State) ReportError(err error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.lastError = err
+	this.lastErrorTime = config.TimeNow()
+}
+
+func (this *State) Check(ctx context.Context) error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.err != nil {
+		return this.err
+	}
+	if this.lastError != nil && config.TimeNow().Sub(this.lastErrorTime) < ErrorExpiration {
+		return fmt.Errorf("last error at %v: %w", this.lastErrorTime.Format(time.RFC3339), this.lastError)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+const (
+	StatusOk       = "ok"
+	StatusError    = "error"
+	StatusDisabled = "disabled"
+)
+
+// ErrDisabled may be returned by a Check to mark a dependency as not configured
+var ErrDisabled = errors.New("disabled")
+
+type Check func(ctx context.Context) error
+
+type ConceptRepo interface {
+	Status() conceptrepo.Status
+}
+
+type Health struct {
+	config       config.Config
+	conceptRepo  ConceptRepo
+	dependencies []dependency
+	mux          sync.Mutex
+}
+
+type dependency struct {
+	name  string
+	check Check
+}
+
+type Report struct {
+	Ready        bool                `json:"ready"`
+	ConceptRepo  *conceptrepo.Status `json:"concept_repo,omitempty"`
+	Dependencies []DependencyStatus  `json:"dependencies"`
+}
+
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+func New(config config.Config, conceptRepo ConceptRepo) *Health {
+	return &Health{config: config, conceptRepo: conceptRepo}
+}
+
+// Register adds a dependency to the readiness report; failing dependencies are reported but do not change readiness,
+// because the marshaller is still able to handle requests that do not need them
+func (this *Health) Register(name string, check Check) {
+	if this == nil {
+		return
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.dependencies = append(this.dependencies, dependency{name: name, check: check})
+}
+
+// Ready checks all registered dependencies concurrently; the result is only ready if the concept-repo contains concepts
+func (this *Health) Ready(ctx context.Context) (result Report) {
+	if this == nil {
+		return Report{Ready: false, Dependencies: []DependencyStatus{}}
+	}
+	this.mux.Lock()
+	dependencies := append([]dependency{}, this.dependencies...)
+	this.mux.Unlock()
+
+	if this.conceptRepo != nil {
+		status := this.conceptRepo.Status()
+		result.ConceptRepo = &status
+		result.Ready = status.Concepts > 0
+	}
+
+	timeout := time.Duration(this.config.HealthCheckTimeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result.Dependencies = make([]DependencyStatus, len(dependencies))
+	wg := sync.WaitGroup{}
+	for i, d := range dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result.Dependencies[i] = run(ctx, d)
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+func run(ctx context.Context, d dependency) (result DependencyStatus) {
+	result.Name = d.name
+	start := time.Now()
+	err := d.check(ctx)
+	result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	switch {
+	case err == nil:
+		result.Status = StatusOk
+	case errors.Is(err, ErrDisabled):
+		result.Status = StatusDisabled
+	default:
+		result.Status = StatusError
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -22,11 +22,13 @@ import (
 	"time"
 
 	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
 	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/converter"
 	"github.com/SENERGY-Platform/marshaller/lib/devicerepository"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
@@ -35,8 +37,11 @@ import (
 )
 
 func Start(ctx context.Context, conf config.Config) (closed context.Context, err error) {
+	access, err := config.NewTokenProvider(conf)
+	if err != nil {
+		return nil, err
+	}
 	childCtx, cancel := context.WithCancel(ctx)
-	access := config.NewAccess(conf)
 	conceptRepo, err := conceptrepo.New(
 		childCtx,
 		conf,
@@ -60,11 +65,30 @@ func Start(ctx context.Context, conf config.Config) (closed context.Context, err
 	}
 	converter := converter.New(conf, access)
 	marshaller := marshaller.New(converter, conceptRepo, devicerepo)
-	configurableService := configurables.New(conceptRepo)
-
 	marshallerV2 := v2.New(conf, converter, conceptRepo)
+	configurableService := configurables.New(conceptRepo, marshallerV2)
+
+	m, err := metrics.Start(childCtx, conf)
+	if err != nil {
+		conf.GetLogger().Warn("unable to serve metrics", "error", err)
+	}
+	m.ObserveConceptRepo(conceptRepo)
+	m.ObserveDeviceRepository(devicerepo)
+	converter.SetMetrics(m)
+
+	h := health.New(conf, conceptRepo)
+	client := config.HttpClient(access)
+	h.Register("device-repository", health.Reachable(client, conf.DeviceRepositoryUrl))
+	h.Register("converter", health.Reachable(client, conf.ConverterUrl))
+	h.Register("auth", health.Token(conf, access))
+	invalidatorState := health.NewState(nil)
+	h.Register("kafka-invalidator", invalidatorState.Check)
+	err = startCacheInvalidator(childCtx, conf, invalidatorState)
+	if err != nil {
+		conf.GetLogger().Warn("unable to start cache invalidator", "error", err)
+	}
 
-	closed = api.Start(childCtx, conf, marshaller, marshallerV2, configurableService, devicerepo, converter)
+	closed = api.Start(childCtx, conf, marshaller, marshallerV2, configurableService, devicerepo, converter, m, h, conceptRepo)
 	go func() {
 		<-closed.Done()
 		cancel()
@@ -73,10 +97,16 @@ func Start(ctx context.Context, conf config.Config) (closed context.Context, err
 }
 
 func StartCacheInvalidator(ctx context.Context, conf config.Config) error {
+	return startCacheInvalidator(ctx, conf, health.NewState(nil))
+}
+
+// startCacheInvalidator reports its state to the kafka-invalidator health check; errors of the running consumer are reported temporarily
+func startCacheInvalidator(ctx context.Context, conf config.Config, state *health.State) error {
 	if conf.KafkaUrl == "" || conf.KafkaUrl == "-" {
+		state.Set(health.ErrDisabled)
 		return nil
 	}
-	return invalidator.StartCacheInvalidatorAll(ctx, kafka.Config{
+	err := invalidator.StartCacheInvalidatorAll(ctx, kafka.Config{
 		KafkaUrl:               conf.KafkaUrl,
 		StartOffset:            kafka.LastOffset,
 		Debug:                  conf.Debug,
@@ -84,7 +114,10 @@ func StartCacheInvalidator(ctx context.Context, conf config.Config) error {
 		InitTopic:              conf.InitTopics,
 		OnError: func(err error) {
 			conf.GetLogger().Error("cache invalidator error", "error", err)
+			state.ReportError(err)
 			debug.PrintStack()
 		},
 	}, conf.CacheInvalidationKafkaTopics, nil)
+	state.Set(err)
+	return err
 }
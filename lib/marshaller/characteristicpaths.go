@@ -18,8 +18,10 @@ package marshaller
 
 import (
 	"errors"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/mapping"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	"net/http"
+	"strings"
 )
 
 type CharacteristicsPathResponse struct {
@@ -27,6 +29,18 @@ type CharacteristicsPathResponse struct {
 	ServiceCharacteristicId string `json:"service_characteristic_id"`
 }
 
+// CharacteristicsPathMatch is a variable with a characteristic sharing a concept with the requested characteristic.
+// Variable length lists are represented by the '*' segment in Path (as in the service model);
+// AllElementsPath selects the matching variable of every list element as v2 path expression (e.g. 'values[*].temperature').
+type CharacteristicsPathMatch struct {
+	Path                    string `json:"path"`
+	AllElementsPath         string `json:"all_elements_path,omitempty"` //only set if Path contains '*' segments
+	ServiceCharacteristicId string `json:"service_characteristic_id"`
+	ConceptId               string `json:"concept_id"`
+	FunctionId              string `json:"function_id"`
+	AspectId                string `json:"aspect_id"`
+}
+
 var ErrCharacteristicNotFoundInService = errors.New("characteristic not in service")
 
 func (this *Marshaller) GetServiceCharacteristicPath(service model.Service, characteristicId string) (result CharacteristicsPathResponse, err error, code int) {
@@ -71,3 +85,84 @@ func getPathOfCharacteristicInContentVariable(variable model.ContentVariable, ch
 	}
 	return "", false
 }
+
+// GetServiceCharacteristicPaths returns all variables of the service outputs or, with direction model.DirectionInputs, inputs
+// with a root characteristic sharing a concept with characteristicId. Variables below a match are not searched.
+func (this *Marshaller) GetServiceCharacteristicPaths(service model.Service, characteristicId string, direction string) (result []CharacteristicsPathMatch, err error, code int) {
+	if direction == "" {
+		direction = model.DirectionOutputs
+	}
+	if direction != model.DirectionOutputs && direction != model.DirectionInputs {
+		return nil, errors.New("unknown direction " + direction), http.StatusBadRequest
+	}
+	conceptIds, err := this.ConceptRepo.GetConceptsOfCharacteristic(characteristicId)
+	if err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	contents := service.Outputs
+	if direction == model.DirectionInputs {
+		contents = service.Inputs
+	}
+	result = []CharacteristicsPathMatch{}
+	for _, content := range contents {
+		result = append(result, this.findCharacteristicPathsInContentVariable([]string{}, content.ContentVariable, conceptIds)...)
+	}
+	return result, nil, http.StatusOK
+}
+
+func (this *Marshaller) findCharacteristicPathsInContentVariable(currentPath []string, variable model.ContentVariable, conceptIds []string) (result []CharacteristicsPathMatch) {
+	currentPath = append(currentPath, variable.Name)
+	if conceptId, ok := this.getSharedConcept(variable.CharacteristicId, conceptIds); ok {
+		match := CharacteristicsPathMatch{
+			Path:                    strings.Join(currentPath, "."),
+			ServiceCharacteristicId: variable.CharacteristicId,
+			ConceptId:               conceptId,
+			FunctionId:              variable.FunctionId,
+			AspectId:                variable.AspectId,
+		}
+		if contains(currentPath, mapping.VAR_LEN_PLACEHOLDER) {
+			match.AllElementsPath = allElementsPath(currentPath)
+		}
+		return []CharacteristicsPathMatch{match}
+	}
+	for _, sub := range variable.SubContentVariables {
+		result = append(result, this.findCharacteristicPathsInContentVariable(currentPath, sub, conceptIds)...)
+	}
+	return result
+}
+
+// getSharedConcept only considers root characteristics; sub characteristics are matched by their parent variable
+func (this *Marshaller) getSharedConcept(characteristicId string, conceptIds []string) (conceptId string, ok bool) {
+	if characteristicId == "" {
+		return "", false
+	}
+	roots := this.ConceptRepo.GetRootCharacteristics([]string{characteristicId})
+	if len(roots) != 1 || roots[0] != characteristicId {
+		return "", false
+	}
+	candidates, err := this.ConceptRepo.GetConceptsOfCharacteristic(characteristicId)
+	if err != nil {
+		return "", false
+	}
+	for _, candidate := range candidates {
+		if contains(conceptIds, candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func allElementsPath(path []string) string {
+	result := ""
+	for i, segment := range path {
+		switch {
+		case segment == mapping.VAR_LEN_PLACEHOLDER:
+			result += "[*]"
+		case i == 0:
+			result += segment
+		default:
+			result += "." + segment
+		}
+	}
+	return result
+}
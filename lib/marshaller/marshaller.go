@@ -49,3 +49,10 @@ func New(converter Converter, concepts ConceptRepo, devicerepo DeviceRepository)
 		devicerepo:  devicerepo,
 	}
 }
+
+// WithDeviceRepository returns a copy of the Marshaller using devicerepo (e.g. to load device-types with the callers token)
+func (this *Marshaller) WithDeviceRepository(devicerepo DeviceRepository) *Marshaller {
+	result := *this
+	result.devicerepo = devicerepo
+	return &result
+}
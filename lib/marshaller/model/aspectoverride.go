@@ -0,0 +1,84 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import "strings"
+
+// AspectOverrideAttributePrefix marks device attributes replacing the aspect of a service variable for this device.
+// The rest of the key is '<service local id>/<path>' with the path as used by v2 path options (e.g. 'getTemperature/value.temperature'),
+// the attribute value is the aspect id (e.g. 'marshaller/aspect_override/getTemperature/value.temperature' = 'urn:infai:ses:aspect:outside_air').
+const AspectOverrideAttributePrefix = "marshaller/aspect_override/"
+
+// WithAspectOverrides returns a copy of deviceType with the aspect overrides of the device attributes applied
+func WithAspectOverrides(deviceType DeviceType, device Device) DeviceType {
+	overrides := map[string]map[string]string{} //service local id to path to aspect id
+	for _, attribute := range device.Attributes {
+		if !strings.HasPrefix(attribute.Key, AspectOverrideAttributePrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(attribute.Key, AspectOverrideAttributePrefix)
+		index := strings.LastIndex(key, "/")
+		if index <= 0 || index == len(key)-1 {
+			continue
+		}
+		serviceLocalId, path := key[:index], key[index+1:]
+		if overrides[serviceLocalId] == nil {
+			overrides[serviceLocalId] = map[string]string{}
+		}
+		overrides[serviceLocalId][path] = strings.TrimSpace(attribute.Value)
+	}
+	if len(overrides) == 0 {
+		return deviceType
+	}
+	services := make([]Service, len(deviceType.Services))
+	for i, service := range deviceType.Services {
+		if paths, ok := overrides[service.LocalId]; ok {
+			service.Inputs = withAspectOverridesInContents(service.Inputs, paths)
+			service.Outputs = withAspectOverridesInContents(service.Outputs, paths)
+		}
+		services[i] = service
+	}
+	deviceType.Services = services
+	return deviceType
+}
+
+func withAspectOverridesInContents(contents []Content, overrides map[string]string) []Content {
+	result := make([]Content, len(contents))
+	for i, content := range contents {
+		content.ContentVariable = withAspectOverridesInVariable(content.ContentVariable, "", overrides)
+		result[i] = content
+	}
+	return result
+}
+
+func withAspectOverridesInVariable(variable ContentVariable, parentPath string, overrides map[string]string) ContentVariable {
+	path := variable.Name
+	if parentPath != "" {
+		path = parentPath + "." + variable.Name
+	}
+	if aspectId, ok := overrides[path]; ok {
+		variable.AspectId = aspectId
+	}
+	if len(variable.SubContentVariables) > 0 {
+		subs := make([]ContentVariable, len(variable.SubContentVariables))
+		for i, sub := range variable.SubContentVariables {
+			subs[i] = withAspectOverridesInVariable(sub, path, overrides)
+		}
+		variable.SubContentVariables = subs
+	}
+	return variable
+}
@@ -35,3 +35,15 @@ const (
 )
 
 type ContentVariable = models.ContentVariable
+
+// SerializationOptionMaxLengthPrefix limits the element count of list variables, e.g. "list/max_length:10"
+const SerializationOptionMaxLengthPrefix = "list/max_length:"
+
+// SerializationOptionSeparatorPrefix defines the separator of plain text contents sharing a protocol segment, e.g. "plaintext/separator:;"
+const SerializationOptionSeparatorPrefix = "plaintext/separator:"
+
+// directions of path searches
+const (
+	DirectionInputs  = "inputs"
+	DirectionOutputs = "outputs"
+)
@@ -0,0 +1,407 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pathexpr parses v2 path expressions.
+//
+// Segments are separated by dots; '\' escapes the following character (e.g. 'a\.b' is the single name "a.b").
+// Additional selectors may follow a name in brackets:
+//   - [*] selects all elements of a list or structure
+//   - [2], [-1] select elements by index; negative indexes count from the end
+//   - ['a.b'] selects the element named a.b
+//   - [?(@.type=='temp')] selects the elements matching the filter; supported operators are ==, !=, <, <=, >, >=;
+//     without operator (e.g. [?(@.type)]) elements containing the field are selected
+//
+// A plain segment consisting of a negative number (e.g. 'list.-1') is handled like a negative index.
+// The plain '*' segment is not a wildcard but the name used for variable length lists.
+package pathexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Segment struct {
+	Name     string //used if Index is nil, Wildcard is false and Filter is nil
+	Index    *int
+	Wildcard bool
+	Filter   *Filter
+}
+
+type Filter struct {
+	Path     Expression //relative to the element; may only contain names and indexes
+	Operator string     //empty to check for existence
+	Value    interface{}
+}
+
+type Expression []Segment
+
+// Child is a named element of a list or structure; list elements are named by their index
+type Child struct {
+	Name  string
+	Value interface{}
+}
+
+// Match is a concrete path selected by an Expression
+type Match struct {
+	Path  []string
+	Value interface{}
+}
+
+var negativeIndex = regexp.MustCompile(`^-\d+$`)
+
+var operators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// IsExpression returns true if path uses syntax beyond plain dot separated names
+func IsExpression(path string) bool {
+	if strings.ContainsAny(path, `[\`) {
+		return true
+	}
+	for _, part := range strings.Split(path, ".") {
+		if negativeIndex.MatchString(part) {
+			return true
+		}
+	}
+	return false
+}
+
+func Parse(path string) (result Expression, err error) {
+	name := strings.Builder{}
+	hasName := false
+	escaped := false
+	expectSegment := true
+	flush := func() {
+		if !hasName {
+			return
+		}
+		str := name.String()
+		if !escaped && negativeIndex.MatchString(str) {
+			index, _ := strconv.Atoi(str)
+			result = append(result, Segment{Index: &index})
+		} else {
+			result = append(result, Segment{Name: str})
+		}
+		name.Reset()
+		hasName = false
+		escaped = false
+	}
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			if i+1 >= len(path) {
+				return result, errors.New("invalid path expression: trailing escape character")
+			}
+			i++
+			name.WriteByte(path[i])
+			hasName = true
+			escaped = true
+			expectSegment = false
+		case '.':
+			if expectSegment {
+				return result, errors.New("invalid path expression: empty segment in " + path)
+			}
+			flush()
+			expectSegment = true
+		case '[':
+			flush()
+			end, segment, err := parseBracket(path, i)
+			if err != nil {
+				return result, err
+			}
+			result = append(result, segment)
+			i = end
+			expectSegment = false
+		case ']':
+			return result, errors.New("invalid path expression: unexpected ] in " + path)
+		default:
+			name.WriteByte(path[i])
+			hasName = true
+			expectSegment = false
+		}
+	}
+	if expectSegment && len(path) > 0 {
+		return result, errors.New("invalid path expression: empty segment in " + path)
+	}
+	flush()
+	return result, nil
+}
+
+// IsMulti returns true if the expression may select more than one element
+func (this Expression) IsMulti() bool {
+	for _, segment := range this {
+		if segment.Wildcard || segment.Filter != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the indexes of the children matched by the segment
+func (this Segment) Select(children []Child, isList bool) (result []int) {
+	switch {
+	case this.Wildcard:
+		for i := range children {
+			result = append(result, i)
+		}
+	case this.Filter != nil:
+		for i, child := range children {
+			if this.Filter.Match(child.Value) {
+				result = append(result, i)
+			}
+		}
+	case this.Index != nil:
+		index := *this.Index
+		if isList {
+			if index < 0 {
+				index = len(children) + index
+			}
+			if index >= 0 && index < len(children) {
+				result = append(result, index)
+			}
+		} else if index >= 0 {
+			return Segment{Name: strconv.Itoa(index)}.Select(children, isList)
+		}
+	default:
+		for i, child := range children {
+			if child.Name == this.Name {
+				result = append(result, i)
+			}
+		}
+	}
+	return result
+}
+
+// Resolve returns all elements of value selected by the expression
+func (this Expression) Resolve(value interface{}) (result []Match) {
+	result = []Match{{Path: []string{}, Value: value}}
+	for _, segment := range this {
+		next := []Match{}
+		for _, match := range result {
+			children, isList := ChildrenOf(match.Value)
+			for _, i := range segment.Select(children, isList) {
+				path := append(append([]string{}, match.Path...), children[i].Name)
+				next = append(next, Match{Path: path, Value: children[i].Value})
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// ChildrenOf returns the elements of lists and maps; map elements are sorted by name
+func ChildrenOf(value interface{}) (result []Child, isList bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		for i, element := range v {
+			result = append(result, Child{Name: strconv.Itoa(i), Value: element})
+		}
+		return result, true
+	case map[string]interface{}:
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			result = append(result, Child{Name: key, Value: v[key]})
+		}
+	}
+	return result, false
+}
+
+func (this Filter) Match(value interface{}) bool {
+	matches := this.Path.Resolve(value)
+	if len(matches) == 0 {
+		return false
+	}
+	if this.Operator == "" {
+		return true
+	}
+	actual := matches[0].Value
+	actualNumber, actualIsNumber := toNumber(actual)
+	expectedNumber, expectedIsNumber := toNumber(this.Value)
+	switch this.Operator {
+	case "==":
+		if actualIsNumber && expectedIsNumber {
+			return actualNumber == expectedNumber
+		}
+		return reflect.DeepEqual(actual, this.Value)
+	case "!=":
+		if actualIsNumber && expectedIsNumber {
+			return actualNumber != expectedNumber
+		}
+		return !reflect.DeepEqual(actual, this.Value)
+	}
+	var compared int
+	actualString, actualIsString := actual.(string)
+	expectedString, expectedIsString := this.Value.(string)
+	switch {
+	case actualIsNumber && expectedIsNumber:
+		compared = compare(actualNumber, expectedNumber)
+	case actualIsString && expectedIsString:
+		compared = strings.Compare(actualString, expectedString)
+	default:
+		return false
+	}
+	switch this.Operator {
+	case "<":
+		return compared < 0
+	case "<=":
+		return compared <= 0
+	case ">":
+		return compared > 0
+	case ">=":
+		return compared >= 0
+	}
+	return false
+}
+
+func parseBracket(path string, start int) (end int, segment Segment, err error) {
+	var quote byte
+	depth := 0 //brackets nested in filter paths (e.g. [?(@[0]==1)])
+	for end = start + 1; end < len(path); end++ {
+		c := path[end]
+		switch {
+		case quote != 0 && c == '\\':
+			end++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == 0 && c == '[':
+			depth++
+		case quote == 0 && c == ']' && depth > 0:
+			depth--
+		case quote == 0 && c == ']':
+			segment, err = parseSelector(strings.TrimSpace(path[start+1 : end]))
+			return end, segment, err
+		}
+	}
+	return end, segment, errors.New("invalid path expression: missing ] in " + path)
+}
+
+func parseSelector(selector string) (segment Segment, err error) {
+	switch {
+	case selector == "*":
+		return Segment{Wildcard: true}, nil
+	case strings.HasPrefix(selector, "?(") && strings.HasSuffix(selector, ")"):
+		filter, err := parseFilter(strings.TrimSpace(selector[2 : len(selector)-1]))
+		return Segment{Filter: &filter}, err
+	case isQuoted(selector):
+		name, err := unquote(selector)
+		return Segment{Name: name}, err
+	default:
+		index, err := strconv.Atoi(selector)
+		if err != nil {
+			return segment, errors.New("invalid path expression: unknown selector [" + selector + "]")
+		}
+		return Segment{Index: &index}, nil
+	}
+}
+
+func parseFilter(filter string) (result Filter, err error) {
+	if !strings.HasPrefix(filter, "@") {
+		return result, errors.New("invalid path expression: filter must start with @")
+	}
+	left := filter
+	right := ""
+	for i := 0; i < len(filter) && result.Operator == ""; i++ {
+		if filter[i] == '\'' || filter[i] == '"' {
+			break
+		}
+		for _, operator := range operators {
+			if strings.HasPrefix(filter[i:], operator) {
+				result.Operator = operator
+				left = strings.TrimSpace(filter[:i])
+				right = strings.TrimSpace(filter[i+len(operator):])
+				break
+			}
+		}
+	}
+	left = strings.TrimPrefix(left, "@")
+	left = strings.TrimPrefix(left, ".")
+	result.Path, err = Parse(left)
+	if err != nil {
+		return result, err
+	}
+	if result.Path.IsMulti() {
+		return result, errors.New("invalid path expression: filter path may not contain wildcards or filters")
+	}
+	if result.Operator == "" {
+		return result, nil
+	}
+	if isQuoted(right) {
+		result.Value, err = unquote(right)
+		return result, err
+	}
+	err = json.Unmarshal([]byte(right), &result.Value)
+	if err != nil {
+		return result, errors.New("invalid path expression: unknown filter value " + right)
+	}
+	return result, nil
+}
+
+func isQuoted(str string) bool {
+	return len(str) >= 2 && (str[0] == '\'' || str[0] == '"') && str[len(str)-1] == str[0]
+}
+
+func unquote(str string) (string, error) {
+	result := strings.Builder{}
+	inner := str[1 : len(str)-1]
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' {
+			i++
+			if i >= len(inner) {
+				return "", errors.New("invalid path expression: trailing escape character")
+			}
+		}
+		result.WriteByte(inner[i])
+	}
+	return result.String(), nil
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func compare(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pathexpr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	index := func(i int) *int {
+		return &i
+	}
+	cases := []struct {
+		path     string
+		expected Expression
+	}{
+		{path: "", expected: nil},
+		{path: "a.b", expected: Expression{{Name: "a"}, {Name: "b"}}},
+		{path: `a\.b`, expected: Expression{{Name: "a.b"}}},
+		{path: `a.\-1`, expected: Expression{{Name: "a"}, {Name: "-1"}}},
+		{path: "a.-1", expected: Expression{{Name: "a"}, {Index: index(-1)}}},
+		{path: "a.*", expected: Expression{{Name: "a"}, {Name: "*"}}},
+		{path: "a[*].b", expected: Expression{{Name: "a"}, {Wildcard: true}, {Name: "b"}}},
+		{path: "a[2]", expected: Expression{{Name: "a"}, {Index: index(2)}}},
+		{path: "a['b.c']", expected: Expression{{Name: "a"}, {Name: "b.c"}}},
+		{path: `a["b\"c"]`, expected: Expression{{Name: "a"}, {Name: `b"c`}}},
+		{path: "a[?(@.type)]", expected: Expression{{Name: "a"}, {Filter: &Filter{Path: Expression{{Name: "type"}}}}}},
+		{path: "a[?(@.type=='temp')]", expected: Expression{{Name: "a"}, {Filter: &Filter{Path: Expression{{Name: "type"}}, Operator: "==", Value: "temp"}}}},
+		{path: "a[?(@.value >= 2)]", expected: Expression{{Name: "a"}, {Filter: &Filter{Path: Expression{{Name: "value"}}, Operator: ">=", Value: float64(2)}}}},
+		{path: "a[?(@[0]==1)]", expected: Expression{{Name: "a"}, {Filter: &Filter{Path: Expression{{Index: index(0)}}, Operator: "==", Value: float64(1)}}}},
+		{path: "a[?(@.b['c]'])].d", expected: Expression{{Name: "a"}, {Filter: &Filter{Path: Expression{{Name: "b"}, {Name: "c]"}}}}, {Name: "d"}}},
+		{path: "a[?(@.name=='a<b')]", expected: Expression{{Name: "a"}, {Filter: &Filter{Path: Expression{{Name: "name"}}, Operator: "==", Value: "a<b"}}}},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			actual, err := Parse(c.path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("\n%#v\n%#v", actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "trailing escape", path: `a\`, expected: "trailing escape character"},
+		{name: "double dot", path: "a..b", expected: "empty segment"},
+		{name: "leading dot", path: ".a", expected: "empty segment"},
+		{name: "trailing dot", path: "a.", expected: "empty segment"},
+		{name: "unexpected bracket", path: "a]", expected: "unexpected ]"},
+		{name: "missing bracket", path: "a[", expected: "missing ]"},
+		{name: "missing bracket after quote", path: "a['b]", expected: "missing ]"},
+		{name: "missing nested bracket", path: "a[?(@[0)]", expected: "missing ]"},
+		{name: "unknown selector", path: "a[foo]", expected: "unknown selector [foo]"},
+		{name: "empty selector", path: "a[]", expected: "unknown selector []"},
+		{name: "filter without @", path: "a[?(type=='x')]", expected: "filter must start with @"},
+		{name: "wildcard in filter", path: "a[?(@[*].b)]", expected: "may not contain wildcards or filters"},
+		{name: "filter in filter", path: "a[?(@[?(@.b)].c)]", expected: "may not contain wildcards or filters"},
+		{name: "invalid filter path", path: "a[?(@..b)]", expected: "empty segment"},
+		{name: "unknown filter value", path: "a[?(@.type==temp)]", expected: "unknown filter value temp"},
+		{name: "missing filter value", path: "a[?(@.type==)]", expected: "unknown filter value"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := Parse(c.path)
+			if err == nil {
+				t.Error("expected error")
+				return
+			}
+			if !strings.Contains(err.Error(), c.expected) {
+				t.Error(err)
+			}
+		})
+	}
+}
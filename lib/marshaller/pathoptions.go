@@ -17,6 +17,7 @@
 package marshaller
 
 import (
+	"errors"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	"net/http"
 	"sort"
@@ -27,12 +28,20 @@ type PathOptionsResultElement struct {
 	ServiceId              string            `json:"service_id"`
 	JsonPath               []string          `json:"json_path"`
 	PathToCharacteristicId map[string]string `json:"path_to_characteristic_id"`
+	DeviceId               string            `json:"device_id,omitempty"`
+	DeviceName             string            `json:"device_name,omitempty"`
+	DeviceLocalId          string            `json:"device_local_id,omitempty"`
 }
 
-func (this *Marshaller) GetPathOption(deviceTypeIds []string, functionId string, aspectId string, characteristicIdFilter []string, withEnvelope bool) (result map[string][]PathOptionsResultElement, err error, code int) {
+// GetPathOption searches the outputs of services or, with direction model.DirectionInputs, their inputs; the envelope is only used for outputs
+func (this *Marshaller) GetPathOption(deviceTypeIds []string, functionId string, aspectId string, characteristicIdFilter []string, withEnvelope bool, direction string) (result map[string][]PathOptionsResultElement, err error, code int) {
+	direction, withEnvelope, err = pathOptionDirection(direction, withEnvelope)
+	if err != nil {
+		return nil, err, http.StatusBadRequest
+	}
 	result = map[string][]PathOptionsResultElement{}
 	for _, deviceTypeId := range deviceTypeIds {
-		result[deviceTypeId], err, code = this.getPathOptionForDeviceType(deviceTypeId, functionId, aspectId, characteristicIdFilter, withEnvelope)
+		result[deviceTypeId], err, code = this.getPathOptionForDeviceType(deviceTypeId, functionId, aspectId, characteristicIdFilter, withEnvelope, direction)
 		if err != nil {
 			return
 		}
@@ -40,18 +49,62 @@ func (this *Marshaller) GetPathOption(deviceTypeIds []string, functionId string,
 	return result, nil, http.StatusOK
 }
 
-func (this *Marshaller) getPathOptionForDeviceType(deviceTypeId string, functionId string, aspectId string, characteristicIdFilter []string, withEnvelope bool) (result []PathOptionsResultElement, err error, code int) {
-	result = []PathOptionsResultElement{}
+// GetDevicePathOption works like GetPathOption for the device-types of devices, respecting their aspect overrides (see model.AspectOverrideAttributePrefix).
+// Results are indexed by device id and annotated with the device.
+func (this *Marshaller) GetDevicePathOption(devices []model.Device, functionId string, aspectId string, characteristicIdFilter []string, withEnvelope bool, direction string) (result map[string][]PathOptionsResultElement, err error, code int) {
+	direction, withEnvelope, err = pathOptionDirection(direction, withEnvelope)
+	if err != nil {
+		return nil, err, http.StatusBadRequest
+	}
+	result = map[string][]PathOptionsResultElement{}
+	for _, device := range devices {
+		dt, err, code := this.devicerepo.GetDeviceType(device.DeviceTypeId)
+		if err != nil {
+			return nil, err, code
+		}
+		elements, err, code := this.getPathOptionForServices(model.WithAspectOverrides(dt, device).Services, functionId, aspectId, characteristicIdFilter, withEnvelope, direction)
+		if err != nil {
+			return nil, err, code
+		}
+		for i := range elements {
+			elements[i].DeviceId = device.Id
+			elements[i].DeviceName = device.Name
+			elements[i].DeviceLocalId = device.LocalId
+		}
+		result[device.Id] = elements
+	}
+	return result, nil, http.StatusOK
+}
+
+func pathOptionDirection(direction string, withEnvelope bool) (string, bool, error) {
+	if direction == "" {
+		direction = model.DirectionOutputs
+	}
+	if direction != model.DirectionOutputs && direction != model.DirectionInputs {
+		return direction, withEnvelope, errors.New("unknown direction " + direction)
+	}
+	if direction == model.DirectionInputs {
+		withEnvelope = false
+	}
+	return direction, withEnvelope, nil
+}
+
+func (this *Marshaller) getPathOptionForDeviceType(deviceTypeId string, functionId string, aspectId string, characteristicIdFilter []string, withEnvelope bool, direction string) (result []PathOptionsResultElement, err error, code int) {
 	dt, err, code := this.devicerepo.GetDeviceType(deviceTypeId)
 	if err != nil {
 		return nil, err, code
 	}
-	services, err := this.filterMatchingServices(dt.Services, functionId, aspectId)
+	return this.getPathOptionForServices(dt.Services, functionId, aspectId, characteristicIdFilter, withEnvelope, direction)
+}
+
+func (this *Marshaller) getPathOptionForServices(services []model.Service, functionId string, aspectId string, characteristicIdFilter []string, withEnvelope bool, direction string) (result []PathOptionsResultElement, err error, code int) {
+	result = []PathOptionsResultElement{}
+	services, err = this.filterMatchingServices(services, functionId, aspectId)
 	if err != nil {
 		return nil, err, http.StatusInternalServerError
 	}
 	for _, service := range services {
-		pathMapping, err, code := this.getPathOptionsForService(service, functionId, characteristicIdFilter)
+		pathMapping, err, code := this.getPathOptionsForService(service, functionId, characteristicIdFilter, direction)
 		if err != nil {
 			return result, err, code
 		}
@@ -78,7 +131,7 @@ func (this *Marshaller) getPathOptionForDeviceType(deviceTypeId string, function
 	return result, nil, http.StatusOK
 }
 
-func (this *Marshaller) getPathOptionsForService(service model.Service, functionId string, characteristicIdFilter []string) (paths map[string]string, err error, code int) {
+func (this *Marshaller) getPathOptionsForService(service model.Service, functionId string, characteristicIdFilter []string, direction string) (paths map[string]string, err error, code int) {
 	characteristics, err := this.ConceptRepo.GetCharacteristicsOfFunction(functionId)
 	if err != nil {
 		return paths, err, http.StatusInternalServerError
@@ -90,7 +143,11 @@ func (this *Marshaller) getPathOptionsForService(service model.Service, function
 	for _, c := range characteristics {
 		characteristicsSet[c] = true
 	}
-	paths = this.findPathsOfCharacteristicsInContents(service.Outputs, characteristicsSet)
+	contents := service.Outputs
+	if direction == model.DirectionInputs {
+		contents = service.Inputs
+	}
+	paths = this.findPathsOfCharacteristicsInContents(contents, characteristicsSet)
 	return paths, nil, http.StatusOK
 }
 
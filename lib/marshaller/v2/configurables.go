@@ -0,0 +1,136 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// applyConfigurables sets the value of each configurable on every input path with a matching concept,
+// except on paths overlapping with explicitPaths; the changed paths are returned.
+// paths are compared as parts, because variable names may contain dots
+func (this *Marshaller) applyConfigurables(inputs []model.Content, explicitPaths [][]string, list []configurables.Configurable) (result []model.Content, changedPaths [][]string, err error) {
+	result = inputs
+	for _, configurable := range list {
+		value, ok := configurableValue(configurable)
+		if !ok {
+			continue
+		}
+		conceptIds, err := this.characteristics.GetConceptsOfCharacteristic(configurable.CharacteristicId)
+		if err != nil {
+			return result, changedPaths, err
+		}
+		paths := [][]string{}
+		for _, content := range result {
+			subPaths, err := this.getPathsOfConcepts(content.ContentVariable, conceptIds, []string{})
+			if err != nil {
+				return result, changedPaths, err
+			}
+			paths = append(paths, subPaths...)
+		}
+		for _, path := range paths {
+			if overlapsAny(path, explicitPaths) {
+				continue
+			}
+			result, err = this.setContentVariableValuesByParts(result, [][]string{path}, configurable.CharacteristicId, value)
+			if err != nil {
+				return result, changedPaths, err
+			}
+			changedPaths = append(changedPaths, path)
+		}
+	}
+	return result, changedPaths, nil
+}
+
+func (this *Marshaller) getPathsOfConcepts(variable model.ContentVariable, conceptIds []string, currentPath []string) (result [][]string, err error) {
+	currentPath = append(append([]string{}, currentPath...), variable.Name)
+	if variable.CharacteristicId != "" {
+		variableConceptIds, err := this.characteristics.GetConceptsOfCharacteristic(variable.CharacteristicId)
+		if err != nil {
+			return result, err
+		}
+		for _, conceptId := range variableConceptIds {
+			if contains(conceptIds, conceptId) {
+				return [][]string{currentPath}, nil
+			}
+		}
+	}
+	for _, sub := range variable.SubContentVariables {
+		subResult, err := this.getPathsOfConcepts(sub, conceptIds, currentPath)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, subResult...)
+	}
+	return result, nil
+}
+
+// overlapsAny is true if path equals one of paths or one is a prefix of the other
+func overlapsAny(path []string, paths [][]string) bool {
+	for _, other := range paths {
+		length := min(len(path), len(other))
+		if slices.Equal(path[:length], other[:length]) {
+			return true
+		}
+	}
+	return false
+}
+
+// configurableValue combines the json encoded values of a configurable to a value of the configurables characteristic;
+// values that are not valid json (e.g. the empty default of strings) are ignored, like in the v1 marshaller
+func configurableValue(configurable configurables.Configurable) (result interface{}, ok bool) {
+	for _, element := range configurable.Values {
+		var value interface{}
+		err := json.Unmarshal([]byte(element.Value), &value)
+		if err != nil {
+			continue
+		}
+		if element.Path == "" {
+			return value, true
+		}
+		result = setValueByPath(result, strings.Split(element.Path, "."), value)
+		ok = true
+	}
+	return result, ok
+}
+
+func setValueByPath(target interface{}, path []string, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	next, rest := path[0], path[1:]
+	if index, err := strconv.Atoi(next); err == nil && index >= 0 {
+		list, _ := target.([]interface{})
+		for len(list) <= index {
+			list = append(list, nil)
+		}
+		list[index] = setValueByPath(list[index], rest, value)
+		return list
+	}
+	obj, isMap := target.(map[string]interface{})
+	if !isMap {
+		obj = map[string]interface{}{}
+	}
+	obj[next] = setValueByPath(obj[next], rest, value)
+	return obj
+}
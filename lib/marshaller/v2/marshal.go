@@ -26,33 +26,99 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
-	"github.com/SENERGY-Platform/marshaller/lib/marshaller/serialization"
 	"github.com/SENERGY-Platform/models/go/models"
 )
 
-func (this *Marshaller) Marshal(protocol model.Protocol, service model.Service, data []model.MarshallingV2RequestData) (result map[string]string, err error) {
+type MarshalOptions struct {
+	Configurables []configurables.Configurable
+	Validate      bool //validate data against the characteristics and the resulting variable values against their characteristics and types
+
+	SegmentTemplates map[string]string //protocol segment name to template; overrides templates from service attributes (see SegmentTemplateAttributePrefix)
+	DeviceId         string            //optional, used in segment templates
+	DeviceLocalId    string            //optional, used in segment templates
+}
+
+// Marshal sets the data values and afterward the configurables on all remaining input paths with a matching concept;
+// values are validated if configured by config.ValidateMarshallingInput
+func (this *Marshaller) Marshal(protocol model.Protocol, service model.Service, data []model.MarshallingV2RequestData, configurables ...configurables.Configurable) (result map[string]string, err error) {
+	return this.MarshalWithOptions(protocol, service, data, MarshalOptions{
+		Configurables: configurables,
+		Validate:      this.config.ValidateMarshallingInput,
+	})
+}
+
+func (this *Marshaller) MarshalWithOptions(protocol model.Protocol, service model.Service, data []model.MarshallingV2RequestData, options MarshalOptions) (result map[string]string, err error) {
+	if options.Validate {
+		err = this.validateData(data)
+		if err != nil {
+			return result, err
+		}
+	}
+	//variable length lists are expanded in place
+	inputs := []model.Content{}
+	for _, input := range service.Inputs {
+		input.ContentVariable = copyVariable(input.ContentVariable)
+		inputs = append(inputs, input)
+	}
+	service.Inputs = inputs
+
+	explicitPaths := [][]string{}
+	listTemplates := map[string]model.ContentVariable{}
 	for _, value := range data {
+		var pathParts [][]string
 		if len(value.Paths) == 0 && value.FunctionId != "" {
-			value.Paths = this.GetInputPaths(service, value.FunctionId, value.AspectNode)
+			//found paths are not interpreted as expressions
+			for _, path := range this.GetInputPaths(service, value.FunctionId, value.AspectNode) {
+				pathParts = append(pathParts, strings.Split(path, "."))
+			}
+		} else {
+			pathParts, err = resolveInputPaths(service.Inputs, value.Paths)
+			if err != nil {
+				return result, err
+			}
 		}
-		service.Inputs, err = this.setContentVariableValues(service.Inputs, value.Paths, value.CharacteristicId, value.Value)
+		pathParts, err = expandVariableLenLists(service.Inputs, pathParts, listTemplates)
 		if err != nil {
 			return result, err
 		}
+		explicitPaths = append(explicitPaths, pathParts...)
+		service.Inputs, err = this.setContentVariableValuesByParts(service.Inputs, pathParts, value.CharacteristicId, value.Value)
+		if err != nil {
+			return result, err
+		}
+	}
+	var configurablePaths [][]string
+	service.Inputs, configurablePaths, err = this.applyConfigurables(service.Inputs, explicitPaths, options.Configurables)
+	if err != nil {
+		return result, err
 	}
-	return this.contentsToMessage(protocol, service.Inputs)
+	err = validateListLengths(service.Inputs)
+	if err != nil {
+		return result, err
+	}
+	if options.Validate {
+		err = this.validateVariables(service.Inputs, append(explicitPaths, configurablePaths...))
+		if err != nil {
+			return result, err
+		}
+	}
+	result, err = this.contentsToMessage(protocol, service.Inputs)
+	if err != nil {
+		return result, err
+	}
+	return this.renderSegmentTemplates(protocol, service, result, options)
 }
 
-func (this *Marshaller) setContentVariableValues(inputs []model.Content, paths []string, characteristic string, value interface{}) (result []model.Content, err error) {
-	for _, path := range paths {
-		pathParts := strings.Split(path, ".")
-		for _, input := range inputs {
-			input.ContentVariable, err = this.setContentVariableValue(input.ContentVariable, []string{}, pathParts, characteristic, value, nil)
+func (this *Marshaller) setContentVariableValuesByParts(inputs []model.Content, paths [][]string, characteristic string, value interface{}) (result []model.Content, err error) {
+	result = inputs
+	for _, pathParts := range paths {
+		for i, input := range result {
+			result[i].ContentVariable, err = this.setContentVariableValue(input.ContentVariable, []string{}, pathParts, characteristic, value, nil)
 			if err != nil {
 				return result, err
 			}
-			result = append(result, input)
 		}
 	}
 	return result, nil
@@ -298,16 +364,10 @@ func getCharacteristicToPaths(variable model.ContentVariable, currentPath []stri
 
 func (this *Marshaller) contentsToMessage(protocol model.Protocol, inputs []model.Content) (result map[string]string, err error) {
 	result = map[string]string{}
+	segmentNames := []string{}
+	segmentContents := map[string][]model.Content{}
 	for _, input := range inputs {
 		if !input.ContentVariable.IsVoid && !input.ContentVariable.OmitEmpty {
-			_, obj, err := contentVariableToObject(input.ContentVariable)
-			if err != nil {
-				return result, err
-			}
-			s, ok := serialization.Get(input.Serialization)
-			if !ok {
-				return result, errors.New("unknown serialization " + string(input.Serialization))
-			}
 			segmentName := ""
 			for _, segment := range protocol.ProtocolSegments {
 				if segment.Id == input.ProtocolSegmentId {
@@ -315,14 +375,20 @@ func (this *Marshaller) contentsToMessage(protocol model.Protocol, inputs []mode
 					break
 				}
 			}
-			if segmentName != "" {
-				result[segmentName], err = s.Marshal(obj, input.ContentVariable)
-				if err != nil {
-					return result, err
-				}
-			} else {
+			if segmentName == "" {
 				slog.Warn("protocol-segment not found " + input.ProtocolSegmentId)
+				continue
 			}
+			if _, ok := segmentContents[segmentName]; !ok {
+				segmentNames = append(segmentNames, segmentName)
+			}
+			segmentContents[segmentName] = append(segmentContents[segmentName], input)
+		}
+	}
+	for _, segmentName := range segmentNames {
+		result[segmentName], err = marshalSegment(segmentName, segmentContents[segmentName])
+		if err != nil {
+			return result, err
 		}
 	}
 	return result, nil
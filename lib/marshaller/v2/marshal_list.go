@@ -0,0 +1,186 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
+)
+
+// AppendListElement is used as path segment to add a new element to a variable length list (e.g. 'lights.+.brightness')
+const AppendListElement = "+"
+
+// MaxVariableLenListLength limits the expansion of variable length lists without SerializationOptionMaxLengthPrefix option
+const MaxVariableLenListLength = 1000
+
+// expandVariableLenLists replaces the '*' sub-variable of variable length lists with indexed copies
+// if a path addresses an element by index ('lights.3.brightness') or appends one ('lights.+.brightness').
+// missing elements are filled with copies of the '*' sub-variable and its default values.
+// templates stores the replaced '*' sub-variables by list path to allow further expansions of the same list.
+// returned paths use the resulting indexes.
+// appending to other variables and indexes beyond the length of fixed lists are reported as violations.
+func expandVariableLenLists(inputs []model.Content, paths [][]string, templates map[string]model.ContentVariable) (result [][]string, err error) {
+	for _, parts := range paths {
+		parts = append([]string{}, parts...)
+		for i := range inputs {
+			if len(parts) > 0 && inputs[i].ContentVariable.Name == parts[0] {
+				err = expandVariableLenListsInVariable(&inputs[i].ContentVariable, parts, 1, templates)
+				if err != nil {
+					return result, err
+				}
+			}
+		}
+		result = append(result, parts)
+	}
+	return result, nil
+}
+
+func expandVariableLenListsInVariable(variable *model.ContentVariable, parts []string, depth int, templates map[string]model.ContentVariable) error {
+	if depth >= len(parts) {
+		return nil
+	}
+	variablePath := strings.Join(parts[:depth], ".")
+	if variable.Type != model.List && parts[depth] == AppendListElement {
+		return validation.ToError([]validation.Violation{{
+			Path:    variablePath,
+			Reason:  validation.ReasonType,
+			Message: fmt.Sprintf("unable to append element to %v", variable.Type),
+		}})
+	}
+	if variable.Type == model.List {
+		template, isVariableLen := templates[variablePath]
+		if !isVariableLen && len(variable.SubContentVariables) == 1 && variable.SubContentVariables[0].Name == "*" {
+			template = variable.SubContentVariables[0]
+			isVariableLen = true
+		}
+		index, isIndex := -1, false
+		if parts[depth] == AppendListElement {
+			index, isIndex = len(variable.SubContentVariables), true
+		} else if i, err := strconv.Atoi(parts[depth]); err == nil && i >= 0 {
+			index, isIndex = i, true
+		}
+		if !isVariableLen && parts[depth] == AppendListElement {
+			return validation.ToError([]validation.Violation{{
+				Path:    variablePath,
+				Reason:  validation.ReasonType,
+				Message: "elements may only be appended to variable length lists",
+			}})
+		}
+		if !isVariableLen && isIndex && index >= len(variable.SubContentVariables) {
+			return validation.ToError([]validation.Violation{{
+				Path:    variablePath,
+				Reason:  validation.ReasonMaxLength,
+				Message: fmt.Sprintf("index %v exceeds fixed length %v", index, len(variable.SubContentVariables)),
+			}})
+		}
+		if isVariableLen && isIndex {
+			if _, stored := templates[variablePath]; !stored {
+				templates[variablePath] = template
+				variable.SubContentVariables = []model.ContentVariable{}
+				if parts[depth] == AppendListElement {
+					index = 0
+				}
+			}
+			maxLength, ok := listMaxLength(*variable)
+			if !ok || maxLength > MaxVariableLenListLength {
+				maxLength = MaxVariableLenListLength
+			}
+			if index >= maxLength {
+				return validation.ToError([]validation.Violation{{
+					Path:    variablePath,
+					Reason:  validation.ReasonMaxLength,
+					Message: fmt.Sprintf("index %v exceeds max length %v", parts[depth], maxLength),
+				}})
+			}
+			for len(variable.SubContentVariables) <= index {
+				element := copyVariable(template)
+				element.Name = strconv.Itoa(len(variable.SubContentVariables))
+				variable.SubContentVariables = append(variable.SubContentVariables, element)
+			}
+			parts[depth] = strconv.Itoa(index)
+		}
+	}
+	for i := range variable.SubContentVariables {
+		if variable.SubContentVariables[i].Name == parts[depth] {
+			err := expandVariableLenListsInVariable(&variable.SubContentVariables[i], parts, depth+1, templates)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func copyVariable(variable model.ContentVariable) model.ContentVariable {
+	subs := variable.SubContentVariables
+	variable.SubContentVariables = nil
+	for _, sub := range subs {
+		variable.SubContentVariables = append(variable.SubContentVariables, copyVariable(sub))
+	}
+	return variable
+}
+
+// validateListLengths checks list variables against their SerializationOptionMaxLengthPrefix option
+func validateListLengths(inputs []model.Content) error {
+	violations := []validation.Violation{}
+	for _, input := range inputs {
+		violations = append(violations, validateListLength(input.ContentVariable, []string{})...)
+	}
+	return validation.ToError(violations)
+}
+
+func validateListLength(variable model.ContentVariable, currentPath []string) (result []validation.Violation) {
+	currentPath = append(currentPath, variable.Name)
+	if maxLength, ok := listMaxLength(variable); ok && variable.Type == model.List {
+		length := len(variable.SubContentVariables)
+		if length == 1 && variable.SubContentVariables[0].Name == "*" {
+			length = 0
+		}
+		if length > maxLength {
+			result = append(result, validation.Violation{
+				Path:    strings.Join(currentPath, "."),
+				Reason:  validation.ReasonMaxLength,
+				Message: fmt.Sprintf("%v elements exceed max length %v", length, maxLength),
+			})
+		}
+	}
+	for _, sub := range variable.SubContentVariables {
+		result = append(result, validateListLength(sub, currentPath)...)
+	}
+	return result
+}
+
+// listMaxLength returns the smallest SerializationOptionMaxLengthPrefix option of the variable
+func listMaxLength(variable model.ContentVariable) (result int, ok bool) {
+	for _, option := range variable.SerializationOptions {
+		if !strings.HasPrefix(option, model.SerializationOptionMaxLengthPrefix) {
+			continue
+		}
+		maxLength, err := strconv.Atoi(strings.TrimPrefix(option, model.SerializationOptionMaxLengthPrefix))
+		if err != nil {
+			continue
+		}
+		if !ok || maxLength < result {
+			result, ok = maxLength, true
+		}
+	}
+	return result, ok
+}
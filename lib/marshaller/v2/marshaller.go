@@ -42,6 +42,7 @@ type CharacteristicsRepo interface {
 	GetCharacteristic(id string) (characteristic model.Characteristic, err error)
 	GetConcept(id string) (concept model.Concept, err error)
 	GetConceptIdOfFunction(id string) string
+	GetConceptsOfCharacteristic(characteristicId string) (conceptIds []string, err error)
 }
 
 type CharacteristicId = string
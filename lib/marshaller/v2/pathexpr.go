@@ -0,0 +1,79 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/pathexpr"
+)
+
+// resolveInputPaths splits plain paths and resolves pathexpr expressions against the input variables;
+// filters are evaluated on the variable values
+func resolveInputPaths(inputs []model.Content, paths []string) (result [][]string, err error) {
+	roots := []model.ContentVariable{}
+	for _, input := range inputs {
+		roots = append(roots, input.ContentVariable)
+	}
+	for _, path := range paths {
+		if !pathexpr.IsExpression(path) {
+			result = append(result, strings.Split(path, "."))
+			continue
+		}
+		expression, err := pathexpr.Parse(path)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, resolveVariables(expression, roots, false, []string{})...)
+	}
+	return result, nil
+}
+
+func resolveVariables(expression pathexpr.Expression, variables []model.ContentVariable, isList bool, currentPath []string) (result [][]string) {
+	if len(expression) == 0 {
+		return [][]string{currentPath}
+	}
+	children := []pathexpr.Child{}
+	for _, variable := range variables {
+		children = append(children, pathexpr.Child{Name: variable.Name, Value: variableToValue(variable)})
+	}
+	for _, i := range expression[0].Select(children, isList) {
+		variable := variables[i]
+		path := append(append([]string{}, currentPath...), variable.Name)
+		result = append(result, resolveVariables(expression[1:], variable.SubContentVariables, variable.Type == model.List, path)...)
+	}
+	return result
+}
+
+func variableToValue(variable model.ContentVariable) interface{} {
+	if len(variable.SubContentVariables) == 0 {
+		return variable.Value
+	}
+	if variable.Type == model.List {
+		result := []interface{}{}
+		for _, sub := range variable.SubContentVariables {
+			result = append(result, variableToValue(sub))
+		}
+		return result
+	}
+	result := map[string]interface{}{}
+	for _, sub := range variable.SubContentVariables {
+		result[sub.Name] = variableToValue(sub)
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// PathOption is a path as accepted by Marshal and Unmarshal
+type PathOption struct {
+	ServiceId        string `json:"service_id"`
+	Path             string `json:"path"`
+	CharacteristicId string `json:"characteristic_id"`
+	FunctionId       string `json:"function_id"`
+	AspectId         string `json:"aspect_id"`
+	AspectDistance   int    `json:"aspect_distance"` //0 for the requested aspect or if no aspect is requested, 1 for its children, ...
+	DeviceId         string `json:"device_id,omitempty"`
+	DeviceName       string `json:"device_name,omitempty"`
+	DeviceLocalId    string `json:"device_local_id,omitempty"`
+}
+
+// GetPathOptions lists the paths of variables with a function matching functionId (any function if empty)
+// and an aspect in the tree of aspect (any aspect if nil). Variables below a match are not searched.
+// Results are ordered by service, aspect distance and path.
+func (this *Marshaller) GetPathOptions(repo DeviceRepository, services []model.Service, direction string, functionId string, aspect *model.AspectNode, characteristicIdFilter []string) (result []PathOption, err error) {
+	if direction == "" {
+		direction = model.DirectionOutputs
+	}
+	if direction != model.DirectionOutputs && direction != model.DirectionInputs {
+		return nil, errors.New("unknown direction " + direction)
+	}
+	var distances map[string]int
+	if aspect != nil {
+		distances, err = getAspectDistances(repo, *aspect)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result = []PathOption{}
+	for _, service := range services {
+		contents := service.Outputs
+		if direction == model.DirectionInputs {
+			contents = service.Inputs
+		}
+		serviceResult := []PathOption{}
+		for _, content := range contents {
+			serviceResult = append(serviceResult, getPathOptionsOfVariable(service.Id, content.ContentVariable, []string{}, functionId, distances, characteristicIdFilter)...)
+		}
+		sort.SliceStable(serviceResult, func(i, j int) bool {
+			if serviceResult[i].AspectDistance != serviceResult[j].AspectDistance {
+				return serviceResult[i].AspectDistance < serviceResult[j].AspectDistance
+			}
+			return serviceResult[i].Path < serviceResult[j].Path
+		})
+		result = append(result, serviceResult...)
+	}
+	return result, nil
+}
+
+func getPathOptionsOfVariable(serviceId string, variable model.ContentVariable, currentPath []string, functionId string, distances map[string]int, characteristicIdFilter []string) (result []PathOption) {
+	currentPath = append(currentPath, variable.Name)
+	if variable.FunctionId != "" && (functionId == "" || variable.FunctionId == functionId) {
+		distance, ok := 0, true
+		if distances != nil {
+			distance, ok = distances[variable.AspectId]
+		}
+		if ok {
+			if len(characteristicIdFilter) > 0 && !contains(characteristicIdFilter, variable.CharacteristicId) {
+				return nil
+			}
+			return []PathOption{{
+				ServiceId:        serviceId,
+				Path:             strings.Join(currentPath, "."),
+				CharacteristicId: variable.CharacteristicId,
+				FunctionId:       variable.FunctionId,
+				AspectId:         variable.AspectId,
+				AspectDistance:   distance,
+			}}
+		}
+	}
+	for _, sub := range variable.SubContentVariables {
+		result = append(result, getPathOptionsOfVariable(serviceId, sub, currentPath, functionId, distances, characteristicIdFilter)...)
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"text/template"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+// SegmentTemplateAttributePrefix marks service attributes containing a template for the protocol segment named by the rest of the key
+// (e.g. 'marshaller/segment_template/topic'); protocol segments have no attributes, so templates are only read from services
+const SegmentTemplateAttributePrefix = "marshaller/segment_template/"
+
+// SegmentTemplateData is passed to segment templates (text/template syntax),
+// e.g. '{{.DeviceLocalId}}/{{.ServiceLocalId}}' or '{{index .Values "payload.brightness"}}'
+type SegmentTemplateData struct {
+	ServiceId      string
+	ServiceLocalId string
+	DeviceId       string
+	DeviceLocalId  string
+	Segments       map[string]string      //marshalled contents by segment name
+	Values         map[string]interface{} //marshalled variable values by path
+}
+
+// renderSegmentTemplates sets every protocol segment with a template from the service attributes or options.SegmentTemplates;
+// rendered segments replace marshalled contents
+func (this *Marshaller) renderSegmentTemplates(protocol model.Protocol, service model.Service, message map[string]string, options MarshalOptions) (result map[string]string, err error) {
+	templates := map[string]string{}
+	for _, attribute := range service.Attributes {
+		if strings.HasPrefix(attribute.Key, SegmentTemplateAttributePrefix) {
+			templates[strings.TrimPrefix(attribute.Key, SegmentTemplateAttributePrefix)] = attribute.Value
+		}
+	}
+	for segment, tmpl := range options.SegmentTemplates {
+		templates[segment] = tmpl
+	}
+	if len(templates) == 0 {
+		return message, nil
+	}
+
+	data := SegmentTemplateData{
+		ServiceId:      service.Id,
+		ServiceLocalId: service.LocalId,
+		DeviceId:       options.DeviceId,
+		DeviceLocalId:  options.DeviceLocalId,
+		Segments:       message,
+		Values:         map[string]interface{}{},
+	}
+	for _, input := range service.Inputs {
+		if input.ContentVariable.IsVoid || input.ContentVariable.OmitEmpty {
+			continue
+		}
+		name, obj, err := contentVariableToObject(input.ContentVariable)
+		if err != nil {
+			return message, err
+		}
+		for path, value := range this.getPathToValueMapFromObj([]string{name}, obj) {
+			data.Values[path] = value
+		}
+	}
+
+	result = map[string]string{}
+	for segment, value := range message {
+		result[segment] = value
+	}
+	for _, segment := range protocol.ProtocolSegments {
+		tmpl, ok := templates[segment.Name]
+		if !ok {
+			continue
+		}
+		delete(templates, segment.Name)
+		parsed, err := template.New(segment.Name).Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return message, err
+		}
+		buf := &bytes.Buffer{}
+		err = parsed.Execute(buf, data)
+		if err != nil {
+			return message, err
+		}
+		result[segment.Name] = buf.String()
+	}
+	for segment := range templates {
+		return message, errors.New("template for unknown protocol segment " + segment)
+	}
+	return result, nil
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/serialization"
+	"github.com/SENERGY-Platform/models/go/models"
+)
+
+// Multiple contents of one protocol segment are handled by their serialization:
+//   - json: the structures are merged; fields may only be set by more than one content if the values are equal
+//   - xml: like json; all contents need the same root variable name
+//   - plain-text: the values are joined with the separator defined by SerializationOptionSeparatorPrefix
+//
+// on unmarshal each json/xml content receives the fields of its sub variables and plain-text messages are split by the separator.
+// other combinations are errors.
+
+func marshalSegment(segmentName string, contents []model.Content) (result string, err error) {
+	s, ok := serialization.Get(contents[0].Serialization)
+	if !ok {
+		return result, errors.New("unknown serialization " + string(contents[0].Serialization))
+	}
+	if len(contents) == 1 {
+		_, obj, err := contentVariableToObject(contents[0].ContentVariable)
+		if err != nil {
+			return result, err
+		}
+		return s.Marshal(obj, contents[0].ContentVariable)
+	}
+	err = checkSegmentContents(segmentName, contents)
+	if err != nil {
+		return result, err
+	}
+	switch contents[0].Serialization {
+	case models.JSON, models.XML:
+		merged := map[string]interface{}{}
+		for _, content := range contents {
+			_, obj, err := contentVariableToObject(content.ContentVariable)
+			if err != nil {
+				return result, err
+			}
+			m, ok := obj.(map[string]interface{})
+			if !ok {
+				return result, errors.New("unable to merge non structure content " + content.ContentVariable.Name + " in protocol segment " + segmentName)
+			}
+			err = mergeMaps(merged, m)
+			if err != nil {
+				return result, errors.New("unable to merge contents in protocol segment " + segmentName + ": " + err.Error())
+			}
+		}
+		return s.Marshal(merged, combineRootVariables(contents))
+	default:
+		separator, err := getSeparator(segmentName, contents)
+		if err != nil {
+			return result, err
+		}
+		parts := []string{}
+		for _, content := range contents {
+			_, obj, err := contentVariableToObject(content.ContentVariable)
+			if err != nil {
+				return result, err
+			}
+			part, err := s.Marshal(obj, content.ContentVariable)
+			if err != nil {
+				return result, err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, separator), nil
+	}
+}
+
+// unmarshalSegment returns one value per content
+func unmarshalSegment(segmentName string, output string, contents []model.Content) (result []interface{}, err error) {
+	s, ok := serialization.Get(contents[0].Serialization)
+	if !ok {
+		return result, errors.New("unknown serialization " + string(contents[0].Serialization))
+	}
+	if len(contents) == 1 {
+		value, err := s.Unmarshal(output, contents[0].ContentVariable)
+		return []interface{}{value}, err
+	}
+	err = checkSegmentContents(segmentName, contents)
+	if err != nil {
+		return result, err
+	}
+	switch contents[0].Serialization {
+	case models.JSON, models.XML:
+		value, err := s.Unmarshal(output, combineRootVariables(contents))
+		if err != nil {
+			return result, err
+		}
+		for _, content := range contents {
+			result = append(result, projectToVariable(value, content.ContentVariable))
+		}
+		return result, nil
+	default:
+		separator, err := getSeparator(segmentName, contents)
+		if err != nil {
+			return result, err
+		}
+		parts := strings.SplitN(output, separator, len(contents))
+		if len(parts) != len(contents) {
+			return result, errors.New("expected " + strconv.Itoa(len(contents)) + " separated values in protocol segment " + segmentName)
+		}
+		for i, content := range contents {
+			value, err := s.Unmarshal(parts[i], content.ContentVariable)
+			if err != nil {
+				return result, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	}
+}
+
+func checkSegmentContents(segmentName string, contents []model.Content) error {
+	for _, content := range contents {
+		if content.Serialization != contents[0].Serialization {
+			return errors.New("contents of protocol segment " + segmentName + " use different serializations")
+		}
+		switch content.Serialization {
+		case models.JSON, models.PlainText:
+		case models.XML:
+			if content.ContentVariable.Name != contents[0].ContentVariable.Name {
+				return errors.New("xml contents of protocol segment " + segmentName + " need the same root variable name")
+			}
+		default:
+			return errors.New("unable to combine contents with serialization " + string(content.Serialization) + " in protocol segment " + segmentName)
+		}
+	}
+	return nil
+}
+
+// combineRootVariables returns the first root variable with the sub variables of all contents
+func combineRootVariables(contents []model.Content) (result model.ContentVariable) {
+	result = contents[0].ContentVariable
+	result.SubContentVariables = []model.ContentVariable{}
+	for _, content := range contents {
+		result.SubContentVariables = append(result.SubContentVariables, content.ContentVariable.SubContentVariables...)
+	}
+	return result
+}
+
+func getSeparator(segmentName string, contents []model.Content) (result string, err error) {
+	for _, content := range contents {
+		for _, option := range content.ContentVariable.SerializationOptions {
+			if !strings.HasPrefix(option, model.SerializationOptionSeparatorPrefix) {
+				continue
+			}
+			separator := strings.TrimPrefix(option, model.SerializationOptionSeparatorPrefix)
+			if result != "" && separator != result {
+				return "", errors.New("contents of protocol segment " + segmentName + " use different separators")
+			}
+			result = separator
+		}
+	}
+	if result == "" {
+		return "", errors.New("missing " + model.SerializationOptionSeparatorPrefix + " serialization option for plain text contents in protocol segment " + segmentName)
+	}
+	return result, nil
+}
+
+// projectToVariable removes fields of structures not defined as sub variable; structures with '*' sub variables are not changed
+func projectToVariable(value interface{}, variable model.ContentVariable) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok || variable.Type != model.Structure || len(variable.SubContentVariables) == 0 {
+		return value
+	}
+	result := map[string]interface{}{}
+	for _, sub := range variable.SubContentVariables {
+		if sub.Name == "*" {
+			return value
+		}
+		if field, ok := m[sub.Name]; ok {
+			result[sub.Name] = field
+		}
+	}
+	return result
+}
+
+func mergeMaps(target map[string]interface{}, source map[string]interface{}) error {
+	for key, value := range source {
+		existing, ok := target[key]
+		if !ok {
+			target[key] = value
+			continue
+		}
+		existingMap, existingIsMap := existing.(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		if existingIsMap && valueIsMap {
+			err := mergeMaps(existingMap, valueMap)
+			if err != nil {
+				return errors.New(key + "." + err.Error())
+			}
+			continue
+		}
+		if !reflect.DeepEqual(existing, value) {
+			return errors.New(key + ": conflicting values")
+		}
+	}
+	return nil
+}
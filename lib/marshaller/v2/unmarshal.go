@@ -19,9 +19,9 @@ package v2
 import (
 	"errors"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
-	"github.com/SENERGY-Platform/marshaller/lib/marshaller/serialization"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/pathexpr"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
 	"github.com/SENERGY-Platform/models/go/models"
-	"runtime/debug"
 	"strconv"
 	"strings"
 )
@@ -29,12 +29,21 @@ import (
 var PathNotFoundInMessage = errors.New("path not found in message")
 
 func (this *Marshaller) Unmarshal(protocol model.Protocol, service model.Service, characteristicId string, path string, msg map[string]string, outputObjectMap map[string]interface{}) (result interface{}, err error) {
+	result, _, err = this.UnmarshalWithViolations(protocol, service, characteristicId, path, msg, outputObjectMap)
+	return result, err
+}
+
+// UnmarshalWithViolations works like Unmarshal and additionally returns the violations found by the output validation policy of the service
+func (this *Marshaller) UnmarshalWithViolations(protocol model.Protocol, service model.Service, characteristicId string, path string, msg map[string]string, outputObjectMap map[string]interface{}) (result interface{}, violations []validation.Violation, err error) {
+	if pathexpr.IsExpression(path) {
+		return this.unmarshalExpression(protocol, service, characteristicId, path, msg, outputObjectMap)
+	}
 	path = substitudeVariableLenPlaceholderInPath(path)
 
 	if outputObjectMap == nil || len(outputObjectMap) == 0 {
 		outputObjectMap, err = serializeOutput(msg, service, protocol)
 		if err != nil {
-			return result, err
+			return result, violations, err
 		}
 	}
 
@@ -42,13 +51,72 @@ func (this *Marshaller) Unmarshal(protocol model.Protocol, service model.Service
 	value, ok := pathToValue[path]
 	if !ok {
 		if this.config.ReturnUnknownPathAsNull {
-			return nil, nil
+			return nil, violations, nil
 		}
-		return result, PathNotFoundInMessage
+		return result, violations, PathNotFoundInMessage
 	}
 
 	service.Outputs, err = substituteVariableLenListsInOutputs(service.Outputs, pathToValue)
 
+	violations, null, err := this.checkOutput(service, path, pathToValue)
+	if err != nil || null {
+		return nil, violations, err
+	}
+
+	result, err = this.convertOutput(service, characteristicId, path, value)
+	return result, violations, err
+}
+
+// unmarshalExpression returns a list of all values selected by a pathexpr expression with wildcards or filters
+// and otherwise the single selected value
+func (this *Marshaller) unmarshalExpression(protocol model.Protocol, service model.Service, characteristicId string, path string, msg map[string]string, outputObjectMap map[string]interface{}) (result interface{}, violations []validation.Violation, err error) {
+	expression, err := pathexpr.Parse(path)
+	if err != nil {
+		return result, violations, err
+	}
+	if outputObjectMap == nil || len(outputObjectMap) == 0 {
+		outputObjectMap, err = serializeOutput(msg, service, protocol)
+		if err != nil {
+			return result, violations, err
+		}
+	}
+	pathToValue := this.getPathToValueMapFromObj([]string{}, outputObjectMap)
+	service.Outputs, err = substituteVariableLenListsInOutputs(service.Outputs, pathToValue)
+	if err != nil {
+		return result, violations, err
+	}
+
+	results := []interface{}{}
+	for _, match := range expression.Resolve(outputObjectMap) {
+		matchPath := strings.Join(match.Path, ".")
+		matchViolations, null, err := this.checkOutput(service, matchPath, pathToValue)
+		violations = append(violations, matchViolations...)
+		if err != nil {
+			return nil, violations, err
+		}
+		var value interface{}
+		if !null {
+			value, err = this.convertOutput(service, characteristicId, matchPath, match.Value)
+			if err != nil {
+				return nil, violations, err
+			}
+		}
+		results = append(results, value)
+	}
+
+	if expression.IsMulti() {
+		return results, violations, nil
+	}
+	if len(results) == 0 {
+		if this.config.ReturnUnknownPathAsNull {
+			return nil, violations, nil
+		}
+		return nil, violations, PathNotFoundInMessage
+	}
+	return results[0], violations, nil
+}
+
+func (this *Marshaller) convertOutput(service model.Service, characteristicId string, path string, value interface{}) (result interface{}, err error) {
 	//no conversion wanted
 	if characteristicId == "" {
 		return value, nil
@@ -172,23 +240,35 @@ func walkPathToMap[Element any, PathElement func(Element) string, Field func(Ele
 
 func serializeOutput(output map[string]string, service model.Service, protocol model.Protocol) (result map[string]interface{}, err error) {
 	result = map[string]interface{}{}
-	for _, content := range service.Outputs {
-		for _, segment := range protocol.ProtocolSegments {
+	for _, segment := range protocol.ProtocolSegments {
+		output, ok := output[segment.Name]
+		if !ok {
+			continue
+		}
+		contents := []model.Content{}
+		for _, content := range service.Outputs {
 			if segment.Id == content.ProtocolSegmentId {
-				output, ok := output[segment.Name]
-				if ok {
-					marshaller, ok := serialization.Get(content.Serialization)
-					if !ok {
-						debug.PrintStack()
-						return result, errors.New("unknown serialization " + string(content.Serialization))
-					}
-					value, err := marshaller.Unmarshal(output, content.ContentVariable)
-					if err != nil {
-						return result, err
-					}
-					result[content.ContentVariable.Name] = value
+				contents = append(contents, content)
+			}
+		}
+		if len(contents) == 0 {
+			continue
+		}
+		values, err := unmarshalSegment(segment.Name, output, contents)
+		if err != nil {
+			return result, err
+		}
+		for i, content := range contents {
+			existing, existingIsMap := result[content.ContentVariable.Name].(map[string]interface{})
+			value, valueIsMap := values[i].(map[string]interface{})
+			if existingIsMap && valueIsMap {
+				err = mergeMaps(existing, value)
+				if err != nil {
+					return result, err
 				}
+				continue
 			}
+			result[content.ContentVariable.Name] = values[i]
 		}
 	}
 	return
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/pathexpr"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
+)
+
+// ListElement is a single value of a variable length list returned by UnmarshalList
+type ListElement struct {
+	Path             string      `json:"path"`
+	Value            interface{} `json:"value"`
+	CharacteristicId string      `json:"characteristic_id,omitempty"` //characteristic of the list sub-variable, before conversion
+	FunctionId       string      `json:"function_id,omitempty"`
+	AspectId         string      `json:"aspect_id,omitempty"`
+}
+
+// UnmarshalList returns every element selected by the '*' placeholders in path (e.g. 'rooms.*.sensors.*.temperature').
+// each element is converted from the characteristic of its list sub-variable to characteristicId.
+func (this *Marshaller) UnmarshalList(protocol model.Protocol, service model.Service, characteristicId string, path string, msg map[string]string, outputObjectMap map[string]interface{}) (result []ListElement, violations []validation.Violation, err error) {
+	result = []ListElement{}
+	expression, err := pathexpr.Parse(path)
+	if err != nil {
+		return result, violations, err
+	}
+	for i, segment := range expression {
+		if segment.Name == "*" && segment.Index == nil && segment.Filter == nil {
+			expression[i] = pathexpr.Segment{Wildcard: true}
+		}
+	}
+
+	if outputObjectMap == nil || len(outputObjectMap) == 0 {
+		outputObjectMap, err = serializeOutput(msg, service, protocol)
+		if err != nil {
+			return result, violations, err
+		}
+	}
+	pathToValue := this.getPathToValueMapFromObj([]string{}, outputObjectMap)
+	service.Outputs, err = substituteVariableLenListsInOutputs(service.Outputs, pathToValue)
+	if err != nil {
+		return result, violations, err
+	}
+
+	pathToCharacteristic := getPathToCharacteristicFromContents(service.Outputs)
+	pathToFunction := getPathToFunctionFromContents(service.Outputs)
+	pathToAspect := getPathToAspectFromContents(service.Outputs)
+
+	for _, match := range expression.Resolve(outputObjectMap) {
+		elementPath := strings.Join(match.Path, ".")
+		element := ListElement{
+			Path:             elementPath,
+			CharacteristicId: pathToCharacteristic[elementPath],
+			FunctionId:       pathToFunction[elementPath],
+			AspectId:         pathToAspect[elementPath],
+		}
+		elementViolations, null, err := this.checkOutput(service, element.Path, pathToValue)
+		violations = append(violations, elementViolations...)
+		if err != nil {
+			return result, violations, err
+		}
+		if !null {
+			element.Value, err = this.convertOutput(service, characteristicId, element.Path, match.Value)
+			if err != nil {
+				return result, violations, err
+			}
+		}
+		result = append(result, element)
+	}
+	return result, violations, nil
+}
+
+func getPathToAspectFromContents(content []model.Content) (result map[string]string) {
+	result = map[string]string{}
+	for _, c := range content {
+		temp := walkPathToMap(
+			[]string{},
+			c.ContentVariable, func(v model.ContentVariable) string { return v.Name },
+			func(v model.ContentVariable) string { return v.AspectId },
+			func(v model.ContentVariable) []model.ContentVariable { return v.SubContentVariables },
+		)
+		for key, value := range temp {
+			result[key] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
+)
+
+// validateData checks the request values against their characteristics; violation paths start with data.<index>
+func (this *Marshaller) validateData(data []model.MarshallingV2RequestData) error {
+	violations := []validation.Violation{}
+	for i, value := range data {
+		if value.CharacteristicId == "" {
+			continue
+		}
+		characteristic, err := this.characteristics.GetCharacteristic(value.CharacteristicId)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, validation.Characteristic("data."+strconv.Itoa(i), value.Value, characteristic)...)
+	}
+	return validation.ToError(violations)
+}
+
+// validateVariables checks the values of the variables at paths and their descendants against the variable type and characteristic
+func (this *Marshaller) validateVariables(inputs []model.Content, paths [][]string) error {
+	violations := []validation.Violation{}
+	for _, content := range inputs {
+		violations = append(violations, this.validateVariable(content.ContentVariable, []string{}, paths, false)...)
+	}
+	return validation.ToError(violations)
+}
+
+func (this *Marshaller) validateVariable(variable model.ContentVariable, currentPath []string, paths [][]string, selected bool) (result []validation.Violation) {
+	currentPath = append(currentPath, variable.Name)
+	path := strings.Join(currentPath, ".")
+	if !selected {
+		for _, candidate := range paths {
+			if slices.Equal(candidate, currentPath) {
+				selected = true
+				break
+			}
+		}
+	}
+	if selected && variable.Value != nil {
+		result = append(result, validation.Variable(path, variable.Value, variable.Type)...)
+		if variable.CharacteristicId != "" {
+			//characteristics of rewritten list elements are unknown and not checked
+			characteristic, err := this.characteristics.GetCharacteristic(variable.CharacteristicId)
+			if err == nil {
+				result = append(result, validation.Characteristic(path, variable.Value, characteristic)...)
+			}
+		}
+	}
+	for _, sub := range variable.SubContentVariables {
+		result = append(result, this.validateVariable(sub, currentPath, paths, selected)...)
+	}
+	return result
+}
+
+// OutputValidationPolicy returns the configured policy for invalid output values of the service; empty if disabled
+func (this *Marshaller) OutputValidationPolicy(serviceId string) string {
+	if policy, ok := this.config.OutputValidationServicePolicies[serviceId]; ok {
+		return policy
+	}
+	return this.config.OutputValidationPolicy
+}
+
+// checkOutput applies the output validation policy of the service to the value at path;
+// null is true if the value has to be replaced by null
+func (this *Marshaller) checkOutput(service model.Service, path string, pathToValue map[string]interface{}) (violations []validation.Violation, null bool, err error) {
+	policy := this.OutputValidationPolicy(service.Id)
+	switch policy {
+	case validation.PolicyFlag, validation.PolicyNull, validation.PolicyReject:
+	default:
+		return nil, false, nil
+	}
+	violations = this.validateOutput(service.Outputs, path, pathToValue)
+	if len(violations) == 0 {
+		return nil, false, nil
+	}
+	switch policy {
+	case validation.PolicyNull:
+		return violations, true, nil
+	case validation.PolicyReject:
+		return violations, false, validation.ToError(violations)
+	}
+	return violations, false, nil
+}
+
+// validateOutput checks the value at path and its sub values against the variable types
+// and the characteristics of variables without sub variables
+func (this *Marshaller) validateOutput(outputs []model.Content, path string, pathToValue map[string]interface{}) (result []validation.Violation) {
+	pathToVariable := map[string]model.ContentVariable{}
+	for _, content := range outputs {
+		temp := walkPathToMap(
+			[]string{},
+			content.ContentVariable, func(v model.ContentVariable) string { return v.Name },
+			func(v model.ContentVariable) model.ContentVariable { return v },
+			func(v model.ContentVariable) []model.ContentVariable { return v.SubContentVariables },
+		)
+		for key, value := range temp {
+			pathToVariable[key] = value
+		}
+	}
+	for variablePath, variable := range pathToVariable {
+		if variablePath != path && !strings.HasPrefix(variablePath, path+".") {
+			continue
+		}
+		value, ok := pathToValue[variablePath]
+		if !ok {
+			continue
+		}
+		typeViolations := validation.Variable(variablePath, value, variable.Type)
+		result = append(result, typeViolations...)
+		if len(typeViolations) == 0 && len(variable.SubContentVariables) == 0 && variable.CharacteristicId != "" {
+			characteristic, err := this.characteristics.GetCharacteristic(variable.CharacteristicId)
+			if err == nil {
+				result = append(result, validation.Characteristic(variablePath, value, characteristic)...)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+const (
+	ReasonType          = "type"
+	ReasonMin           = "min"
+	ReasonMax           = "max"
+	ReasonAllowedValues = "allowed_values"
+	ReasonRequired      = "required"
+	ReasonMaxLength     = "max_length"
+)
+
+// policies for invalid output values
+const (
+	PolicyFlag   = "flag"   //keep value, report violations
+	PolicyNull   = "null"   //replace value with null, report violations
+	PolicyReject = "reject" //return Error
+)
+
+type Violation struct {
+	Path             string      `json:"path"`
+	CharacteristicId string      `json:"characteristic_id,omitempty"`
+	Reason           string      `json:"reason"`
+	Message          string      `json:"message"`
+	Value            interface{} `json:"value,omitempty"`
+}
+
+// Error is returned if a value does not match its characteristic or variable
+type Error struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (this *Error) Error() string {
+	messages := []string{}
+	for _, v := range this.Violations {
+		messages = append(messages, v.Path+": "+v.Message)
+	}
+	return "invalid value: " + strings.Join(messages, "; ")
+}
+
+// ToError returns nil if violations is empty
+func ToError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Violations: violations}
+}
+
+// Characteristic checks type, min/max, allowed values and the presence of the sub-characteristics of structures.
+// sub-characteristics named "*" are used for every element of lists and maps.
+func Characteristic(path string, value interface{}, characteristic model.Characteristic) (result []Violation) {
+	value, err := normalize(value)
+	if err != nil {
+		return []Violation{{Path: path, CharacteristicId: characteristic.Id, Reason: ReasonType, Message: err.Error()}}
+	}
+	return validateCharacteristic(path, value, characteristic)
+}
+
+func validateCharacteristic(path string, value interface{}, characteristic model.Characteristic) (result []Violation) {
+	violation := func(reason string, message string) []Violation {
+		return []Violation{{Path: path, CharacteristicId: characteristic.Id, Reason: reason, Message: message, Value: value}}
+	}
+	if value == nil {
+		return nil
+	}
+	if !matchesType(value, characteristic.Type) {
+		return violation(ReasonType, fmt.Sprintf("expected %v, got %T", characteristic.Type, value))
+	}
+	if number, ok := value.(float64); ok {
+		if min, ok := toFloat(characteristic.MinValue); ok && number < min {
+			result = append(result, violation(ReasonMin, fmt.Sprintf("%v is less than %v", number, min))...)
+		}
+		if max, ok := toFloat(characteristic.MaxValue); ok && number > max {
+			result = append(result, violation(ReasonMax, fmt.Sprintf("%v is greater than %v", number, max))...)
+		}
+	}
+	if len(characteristic.AllowedValues) > 0 && !isAllowed(value, characteristic.AllowedValues) {
+		result = append(result, violation(ReasonAllowedValues, fmt.Sprintf("%v is not one of %v", value, characteristic.AllowedValues))...)
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, sub := range characteristic.SubCharacteristics {
+			if sub.Name == "*" {
+				for key, element := range v {
+					result = append(result, validateCharacteristic(path+"."+key, element, sub)...)
+				}
+				continue
+			}
+			element, ok := v[sub.Name]
+			if !ok {
+				result = append(result, Violation{Path: path + "." + sub.Name, CharacteristicId: sub.Id, Reason: ReasonRequired, Message: "missing " + sub.Name})
+				continue
+			}
+			result = append(result, validateCharacteristic(path+"."+sub.Name, element, sub)...)
+		}
+	case []interface{}:
+		for _, sub := range characteristic.SubCharacteristics {
+			if sub.Name == "*" {
+				for i, element := range v {
+					result = append(result, validateCharacteristic(path+"."+strconv.Itoa(i), element, sub)...)
+				}
+				continue
+			}
+			index, err := strconv.Atoi(sub.Name)
+			if err != nil {
+				continue
+			}
+			if index >= len(v) {
+				result = append(result, Violation{Path: path + "." + sub.Name, CharacteristicId: sub.Id, Reason: ReasonRequired, Message: "missing " + sub.Name})
+				continue
+			}
+			result = append(result, validateCharacteristic(path+"."+sub.Name, v[index], sub)...)
+		}
+	}
+	return result
+}
+
+// Variable checks if value may be used for a variable of variableType; only primitive types are checked
+func Variable(path string, value interface{}, variableType model.Type) []Violation {
+	value, err := normalize(value)
+	if err != nil {
+		return []Violation{{Path: path, Reason: ReasonType, Message: err.Error()}}
+	}
+	if value == nil {
+		return nil
+	}
+	switch variableType {
+	case model.String, model.Integer, model.Float, model.Boolean:
+		if !matchesType(value, variableType) {
+			return []Violation{{Path: path, Reason: ReasonType, Message: fmt.Sprintf("expected %v, got %T", variableType, value), Value: value}}
+		}
+	}
+	return nil
+}
+
+func matchesType(value interface{}, t model.Type) bool {
+	switch t {
+	case model.String:
+		_, ok := value.(string)
+		return ok
+	case model.Integer:
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case model.Float:
+		_, ok := value.(float64)
+		return ok
+	case model.Boolean:
+		_, ok := value.(bool)
+		return ok
+	case model.Structure:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case model.List:
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+func isAllowed(value interface{}, allowed []interface{}) bool {
+	for _, candidate := range allowed {
+		normalized, err := normalize(candidate)
+		if err == nil && reflect.DeepEqual(value, normalized) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	normalized, err := normalize(value)
+	if err != nil {
+		return 0, false
+	}
+	result, ok := normalized.(float64)
+	return result, ok
+}
+
+func normalize(value interface{}) (result interface{}, err error) {
+	temp, err := json.Marshal(value)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(temp, &result)
+	return result, err
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+func testToken(roles ...string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub":          "test-user",
+		"realm_access": map[string]interface{}{"roles": roles},
+	})
+	return "Bearer " + header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+}
+
+func adminRequestTo(serverUrl string, method string, path string, token string, result interface{}) (int, error) {
+	req, err := http.NewRequest(method, serverUrl+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK && result != nil {
+		err = json.NewDecoder(resp.Body).Decode(result)
+	}
+	return resp.StatusCode, err
+}
+
+func TestConceptRepoAdminEndpointsWithoutJwks(t *testing.T) {
+	code, err := adminRequestTo(ServerUrl, http.MethodGet, "/admin/concept-repo", testToken("user", "admin"), nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if code != http.StatusNotFound {
+		t.Error(code)
+	}
+}
+
+func TestConceptRepoAdminEndpoints(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.Config{AuthJwksUrl: writeTestJwks(t, "test-key", key)}
+	conceptRepo, err := mocks.NewMockConceptRepo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := marshaller.New(mocks.Converter{}, conceptRepo, mocks.DeviceRepo)
+	marshallerv2 := v2.New(conf, mocks.Converter{}, conceptRepo)
+	configurableService := configurables.New(conceptRepo, marshallerv2)
+	server := httptest.NewServer(api.GetRouter(conf, m, marshallerv2, configurableService, mocks.DeviceRepo, nil, metrics.NewMetrics(conf), health.New(conf, conceptRepo), conceptRepo))
+	defer server.Close()
+
+	adminRequest := func(method string, path string, token string, result interface{}) (int, error) {
+		return adminRequestTo(server.URL, method, path, token, result)
+	}
+
+	rejected := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{name: "missing token", expected: http.StatusUnauthorized},
+		{name: "unsigned admin token", token: testToken("user", "admin"), expected: http.StatusUnauthorized},
+		{name: "forged admin token", token: signedTestToken(otherKey, "test-key", time.Now().Add(time.Hour), "user", "admin"), expected: http.StatusUnauthorized},
+		{name: "user token", token: signedTestToken(key, "test-key", time.Now().Add(time.Hour), "user"), expected: http.StatusForbidden},
+	}
+	for _, c := range rejected {
+		t.Run(c.name, func(t *testing.T) {
+			code, err := adminRequest(http.MethodPost, "/admin/concept-repo/reload", c.token, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if code != c.expected {
+				t.Error(code, c.expected)
+			}
+		})
+	}
+
+	admin := signedTestToken(key, "test-key", time.Now().Add(time.Hour), "user", "admin")
+
+	t.Run("concepts", func(t *testing.T) {
+		concepts := map[string]model.Concept{}
+		code, err := adminRequest(http.MethodGet, "/admin/concept-repo/concepts", admin, &concepts)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if code != http.StatusOK {
+			t.Error(code)
+			return
+		}
+		if _, ok := concepts["urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37"]; !ok {
+			t.Error(len(concepts))
+		}
+	})
+	t.Run("characteristics", func(t *testing.T) {
+		characteristics := map[string]model.Characteristic{}
+		code, err := adminRequest(http.MethodGet, "/admin/concept-repo/characteristics", admin, &characteristics)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if code != http.StatusOK || characteristics[temperature.Celsius].Id != temperature.Celsius {
+			t.Error(code, len(characteristics))
+		}
+	})
+	t.Run("functions", func(t *testing.T) {
+		functions := map[string]map[string]interface{}{}
+		code, err := adminRequest(http.MethodGet, "/admin/concept-repo/functions", admin, &functions)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if code != http.StatusOK || functions["function_to_concept"]["urn:infai:ses:controlling-function:99240d90-02dd-4d4f-a47c-069cfe77629c"] != "urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37" {
+			t.Error(code, functions["function_to_concept"])
+		}
+	})
+	t.Run("root characteristics", func(t *testing.T) {
+		roots := map[string]string{}
+		code, err := adminRequest(http.MethodGet, "/admin/concept-repo/root-characteristics", admin, &roots)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if code != http.StatusOK || roots[color.RgbR] != color.Rgb {
+			t.Error(code, roots[color.RgbR])
+		}
+	})
+	t.Run("reload", func(t *testing.T) {
+		before := api.ConceptRepoAdminInfo{}
+		code, err := adminRequest(http.MethodGet, "/admin/concept-repo", admin, &before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if code != http.StatusOK || before.Status.Concepts == 0 {
+			t.Error(code, before)
+			return
+		}
+		after := api.ConceptRepoAdminInfo{}
+		code, err = adminRequest(http.MethodPost, "/admin/concept-repo/reload", admin, &after)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if code != http.StatusOK {
+			t.Error(code)
+			return
+		}
+		if after.Stats.FullLoads != before.Stats.FullLoads+1 || after.Stats.FullLoadFailures != before.Stats.FullLoadFailures {
+			t.Errorf("%#v %#v", before.Stats, after.Stats)
+		}
+		if !after.Status.LoadedAt.After(before.Status.LoadedAt) {
+			t.Error(before.Status.LoadedAt, after.Status.LoadedAt)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/api/util"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+type tokenRecordingDeviceRepo struct {
+	*mocks.DeviceRepoStruct
+	tokens []string
+}
+
+func (this *tokenRecordingDeviceRepo) GetServiceWithToken(token string, serviceId string) (model.Service, error, int) {
+	this.tokens = append(this.tokens, token)
+	return model.Service{Id: serviceId, Name: serviceId}, nil, http.StatusOK
+}
+
+func signedTestToken(key *rsa.PrivateKey, kid string, exp time.Time, roles ...string) string {
+	header, _ := json.Marshal(map[string]interface{}{"alg": "RS256", "typ": "JWT", "kid": kid})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub":          "test-user",
+		"exp":          exp.Unix(),
+		"realm_access": map[string]interface{}{"roles": roles},
+	})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return "Bearer " + unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeTestJwks(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	location := filepath.Join(t.TempDir(), "jwks.json")
+	jwks, _ := json.Marshal(map[string]interface{}{"keys": []map[string]interface{}{{
+		"kid": kid,
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	err := os.WriteFile(location, jwks, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+func TestAuth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.Config{
+		AuthJwksUrl:          writeTestJwks(t, "test-key", key),
+		AuthPublicPaths:      []string{"/health"},
+		AuthEndpointRoles:    map[string]string{"/admin": "admin", "/configurables": "user|admin"},
+		AuthForwardUserToken: true,
+	}
+
+	conceptRepo, err := mocks.NewMockConceptRepo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deviceRepo := &tokenRecordingDeviceRepo{DeviceRepoStruct: mocks.DeviceRepo}
+	m := marshaller.New(mocks.Converter{}, conceptRepo, deviceRepo)
+	marshallerv2 := v2.New(conf, mocks.Converter{}, conceptRepo)
+	configurableService := configurables.New(conceptRepo, marshallerv2)
+	router := api.GetRouter(conf, m, marshallerv2, configurableService, deviceRepo, nil, metrics.NewMetrics(conf), health.New(conf, conceptRepo), conceptRepo)
+	server := httptest.NewServer(util.NewAuth(conf, router))
+	defer server.Close()
+
+	valid := signedTestToken(key, "test-key", time.Now().Add(time.Hour), "user")
+	admin := signedTestToken(key, "test-key", time.Now().Add(time.Hour), "user", "admin")
+
+	request := func(path string, token string) int {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	configurablesPath := "/configurables?characteristicId=" + temperature.Celsius + "&serviceIds=s1"
+
+	cases := []struct {
+		name     string
+		path     string
+		token    string
+		expected int
+	}{
+		{name: "public path", path: "/health/live", expected: http.StatusOK},
+		{name: "missing token", path: configurablesPath, expected: http.StatusUnauthorized},
+		{name: "unsigned token", path: configurablesPath, token: testToken("user"), expected: http.StatusUnauthorized},
+		{name: "foreign key", path: configurablesPath, token: signedTestToken(otherKey, "test-key", time.Now().Add(time.Hour), "user"), expected: http.StatusUnauthorized},
+		{name: "expired token", path: configurablesPath, token: signedTestToken(key, "test-key", time.Now().Add(-time.Minute), "user"), expected: http.StatusUnauthorized},
+		{name: "missing role", path: configurablesPath, token: signedTestToken(key, "test-key", time.Now().Add(time.Hour), "guest"), expected: http.StatusForbidden},
+		{name: "admin path with user", path: "/admin/concept-repo", token: valid, expected: http.StatusForbidden},
+		{name: "admin path with admin", path: "/admin/concept-repo", token: admin, expected: http.StatusOK},
+		{name: "valid token", path: configurablesPath, token: valid, expected: http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if code := request(c.path, c.token); code != c.expected {
+				t.Error(code, c.expected)
+			}
+		})
+	}
+
+	t.Run("forwarded token", func(t *testing.T) {
+		if len(deviceRepo.tokens) != 1 || deviceRepo.tokens[0] != valid {
+			t.Error(deviceRepo.tokens)
+		}
+	})
+}
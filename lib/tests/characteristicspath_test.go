@@ -18,6 +18,7 @@ package tests
 
 import (
 	"fmt"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
 	"io"
 	"net/url"
@@ -79,3 +80,89 @@ func ExampleGet_characteristicsPathNotMatching() {
 	//<nil> 404 characteristic not in service
 
 }
+
+func setCharacteristicsPathMultiService() (serviceId string) {
+	celsius := "urn:infai:ses:characteristic:5ba31623-0ccb-4488-bfb7-f73b50e03b5a"
+	getTemperature := "urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b"
+	serviceId = "characteristicsPathMultiService"
+	mocks.DeviceRepo.SetService(model.Service{
+		Id:      serviceId,
+		LocalId: serviceId,
+		Inputs: []model.Content{{ContentVariable: model.ContentVariable{
+			Name: "target",
+			Type: model.Structure,
+			SubContentVariables: []model.ContentVariable{
+				{Name: "celsius", Type: model.Float, CharacteristicId: celsius, FunctionId: "urn:infai:ses:controlling-function:99240d90-02dd-4d4f-a47c-069cfe77629c", AspectId: "inside_air"},
+			},
+		}}},
+		Outputs: []model.Content{{ContentVariable: model.ContentVariable{
+			Name: "values",
+			Type: model.Structure,
+			SubContentVariables: []model.ContentVariable{
+				{Name: "outside", Type: model.Float, CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "outside_air"},
+				{Name: "rooms", Type: model.List, SubContentVariables: []model.ContentVariable{
+					{Name: "*", Type: model.Structure, SubContentVariables: []model.ContentVariable{
+						{Name: "name", Type: model.String},
+						{Name: "temperature", Type: model.Float, CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "inside_air"},
+					}},
+				}},
+				{Name: "battery", Type: model.Float, CharacteristicId: "urn:infai:ses:characteristic:46f808f4-bde5-4ce8-a61d-1e1d6d7d1e10"},
+			},
+		}}},
+	})
+	return serviceId
+}
+
+func ExampleGet_characteristicsPathOutputs() {
+	serviceId := setCharacteristicsPathMultiService()
+	characteristicId := "urn:infai:ses:characteristic:75b2d113-1d03-4ef8-977a-8dbcbb31a683" //temperature kelvin
+
+	resp, err := get(ServerUrl + "/characteristic-paths/" + url.PathEscape(serviceId) + "/" + url.PathEscape(characteristicId) + "?direction=outputs")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+	result, err := io.ReadAll(resp.Body)
+	fmt.Println(err, resp.StatusCode, string(result))
+
+	//output:
+	//<nil> 200 [{"path":"values.outside","service_characteristic_id":"urn:infai:ses:characteristic:5ba31623-0ccb-4488-bfb7-f73b50e03b5a","concept_id":"urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37","function_id":"urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b","aspect_id":"outside_air"},{"path":"values.rooms.*.temperature","all_elements_path":"values.rooms[*].temperature","service_characteristic_id":"urn:infai:ses:characteristic:5ba31623-0ccb-4488-bfb7-f73b50e03b5a","concept_id":"urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37","function_id":"urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b","aspect_id":"inside_air"}]
+
+}
+
+func ExampleGet_characteristicsPathInputs() {
+	serviceId := setCharacteristicsPathMultiService()
+	characteristicId := "urn:infai:ses:characteristic:75b2d113-1d03-4ef8-977a-8dbcbb31a683" //temperature kelvin
+
+	resp, err := get(ServerUrl + "/characteristic-paths/" + url.PathEscape(serviceId) + "/" + url.PathEscape(characteristicId) + "?direction=inputs")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+	result, err := io.ReadAll(resp.Body)
+	fmt.Println(err, resp.StatusCode, string(result))
+
+	//output:
+	//<nil> 200 [{"path":"target.celsius","service_characteristic_id":"urn:infai:ses:characteristic:5ba31623-0ccb-4488-bfb7-f73b50e03b5a","concept_id":"urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37","function_id":"urn:infai:ses:controlling-function:99240d90-02dd-4d4f-a47c-069cfe77629c","aspect_id":"inside_air"}]
+
+}
+
+func ExampleGet_characteristicsPathUnknownDirection() {
+	serviceId := setCharacteristicsPathMultiService()
+	characteristicId := "urn:infai:ses:characteristic:5ba31623-0ccb-4488-bfb7-f73b50e03b5a" //temperature celsius
+
+	resp, err := get(ServerUrl + "/characteristic-paths/" + url.PathEscape(serviceId) + "/" + url.PathEscape(characteristicId) + "?direction=foo")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+	result, err := io.ReadAll(resp.Body)
+	fmt.Println(err, resp.StatusCode, string(result))
+
+	//output:
+	//<nil> 400 unknown direction foo
+
+}
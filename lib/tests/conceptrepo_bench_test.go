@@ -0,0 +1,128 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/converter/lib/converter/characteristics"
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+// lockedConceptRepo serializes reads like the ConceptRepo did before reads became lock-free; used as benchmark baseline
+type lockedConceptRepo struct {
+	repo *conceptrepo.ConceptRepo
+	mux  sync.Mutex
+}
+
+func (this *lockedConceptRepo) GetCharacteristic(id string) (model.Characteristic, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.repo.GetCharacteristic(id)
+}
+
+func (this *lockedConceptRepo) GetConceptsOfCharacteristic(id string) ([]string, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.repo.GetConceptsOfCharacteristic(id)
+}
+
+func (this *lockedConceptRepo) Load() error {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.repo.Load()
+}
+
+type benchConceptRepo interface {
+	GetCharacteristic(id string) (model.Characteristic, error)
+	GetConceptsOfCharacteristic(id string) ([]string, error)
+	Load() error
+}
+
+func newBenchConceptRepo(b *testing.B, ctx context.Context) *conceptrepo.ConceptRepo {
+	repo, err := mocks.NewMockConceptRepoWithConfig(ctx, config.Config{ConceptRepoRefreshInterval: 42000, LogLevel: "error"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return repo
+}
+
+func benchmarkConceptRepoReads(b *testing.B, repo benchConceptRepo, reload bool) {
+	if reload {
+		done := make(chan struct{})
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					if err := repo.Load(); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			}
+		}()
+		defer wg.Wait()
+		defer close(done)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := repo.GetCharacteristic(characteristics.Celsius); err != nil {
+				b.Error(err)
+				return
+			}
+			if _, err := repo.GetConceptsOfCharacteristic(characteristics.Celsius); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkConceptRepoReads(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := newBenchConceptRepo(b, ctx)
+	b.Run("lock-free", func(b *testing.B) {
+		benchmarkConceptRepoReads(b, repo, false)
+	})
+	b.Run("locked", func(b *testing.B) {
+		benchmarkConceptRepoReads(b, &lockedConceptRepo{repo: repo}, false)
+	})
+}
+
+func BenchmarkConceptRepoReadsDuringReload(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := newBenchConceptRepo(b, ctx)
+	b.Run("lock-free", func(b *testing.B) {
+		benchmarkConceptRepoReads(b, repo, true)
+	})
+	b.Run("locked", func(b *testing.B) {
+		benchmarkConceptRepoReads(b, &lockedConceptRepo{repo: repo}, true)
+	})
+}
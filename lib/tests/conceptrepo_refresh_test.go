@@ -0,0 +1,247 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/testdata"
+)
+
+func TestConceptRepoIncrementalRefresh(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const temperatureConcept = "urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37"
+	const getTemperatureFunction = "urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b"
+
+	repo, update, err := mocks.NewMockConceptRepoWithUpdate(ctx, config.Config{ConceptRepoRefreshInterval: 42000})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	concepts, err := testdata.GetConcepts()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	characteristics, err := testdata.GetCharacteristics()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	functions, err := testdata.GetFunctions()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("concept", func(t *testing.T) {
+		concept := concepts[slices.IndexFunc(concepts, func(c model.Concept) bool { return c.Id == temperatureConcept })]
+		concept.Name = "changed temperature"
+		err = update.SetConcept(concept)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current, err := repo.GetConcept(temperatureConcept)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if current.Name == concept.Name {
+			t.Error("expect unchanged concept before refresh")
+			return
+		}
+		err = repo.RefreshConcept(temperatureConcept)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current, err = repo.GetConcept(temperatureConcept)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if current.Name != concept.Name {
+			t.Error(current.Name)
+		}
+	})
+
+	t.Run("characteristic", func(t *testing.T) {
+		characteristic := characteristics[slices.IndexFunc(characteristics, func(c model.Characteristic) bool { return c.Id == temperature.Celsius })]
+		characteristic.DisplayUnit = "changed"
+		err = update.SetCharacteristic(characteristic)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = repo.RefreshCharacteristic(temperature.Celsius)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current, err := repo.GetCharacteristic(temperature.Celsius)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if current.DisplayUnit != characteristic.DisplayUnit {
+			t.Error(current.DisplayUnit)
+		}
+		conceptIds, err := repo.GetConceptsOfCharacteristic(temperature.Celsius)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !slices.Contains(conceptIds, temperatureConcept) {
+			t.Error(conceptIds)
+		}
+	})
+
+	t.Run("function", func(t *testing.T) {
+		function := functions[slices.IndexFunc(functions, func(f model.Function) bool { return f.Id == getTemperatureFunction })]
+		function.ConceptId = exampleColorConcept
+		err = update.SetFunction(function)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = repo.RefreshFunction(getTemperatureFunction)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if conceptId := repo.GetConceptIdOfFunction(getTemperatureFunction); conceptId != exampleColorConcept {
+			t.Error(conceptId)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		err = repo.RefreshConcept("unknown")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = repo.RefreshFunction("unknown")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err = repo.GetConcept(temperatureConcept); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("full reload", func(t *testing.T) {
+		err = repo.Load()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		current, err := repo.GetConcept(temperatureConcept)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if current.Name != "changed temperature" {
+			t.Error(current.Name)
+		}
+	})
+}
+
+const exampleColorConcept = "example_color"
+
+// run with -race: readers use the published index while refreshes build and publish new ones
+func TestConceptRepoConcurrentRefresh(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const temperatureConcept = "urn:infai:ses:concept:0bc81398-3ed6-4e2b-a6c4-b754583aac37"
+	const getTemperatureFunction = "urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b"
+
+	//spare capacity of CharacteristicIds must not be shared between indexes
+	defaultConcept := model.Concept{Id: "default-concept", CharacteristicIds: make([]string, 0, 4)}
+	defaults := append([]conceptrepo.ConceptRepoDefault{{
+		Concept:         defaultConcept,
+		Characteristics: []model.Characteristic{{Id: "default-characteristic", Type: model.Float}},
+	}}, mocks.ConceptRepoDefaults...)
+
+	repo, _, err := mocks.NewMockConceptRepoWithUpdate(ctx, config.Config{ConceptRepoRefreshInterval: 42000}, defaults...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := repo.GetCharacteristicsOfFunction(getTemperatureFunction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = slices.Clone(expected)
+
+	done := make(chan struct{})
+	readerErr := make(chan error, 1)
+	go func() {
+		defer close(readerErr)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			ids, err := repo.GetCharacteristicsOfFunction(getTemperatureFunction)
+			if err != nil {
+				readerErr <- err
+				return
+			}
+			if !slices.Equal(ids, expected) {
+				readerErr <- fmt.Errorf("unexpected characteristics of function %v", ids)
+				return
+			}
+			concept, err := repo.GetConcept(defaultConcept.Id)
+			if err != nil {
+				readerErr <- err
+				return
+			}
+			if !slices.Equal(concept.CharacteristicIds, []string{"default-characteristic"}) {
+				readerErr <- fmt.Errorf("unexpected characteristics of concept %v", concept.CharacteristicIds)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		err = repo.RefreshConcept(temperatureConcept)
+		if err != nil {
+			t.Error(err)
+		}
+		err = repo.RefreshFunction(getTemperatureFunction)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	close(done)
+	if err := <-readerErr; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+func TestConceptRepoWarmStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	snapshotFile := filepath.Join(t.TempDir(), "concept-repo-snapshot.json")
+
+	repo, err := mocks.NewMockConceptRepoWithConfig(ctx, config.Config{
+		ConceptRepoRefreshInterval: 42000,
+		ConceptRepoSnapshotFile:    snapshotFile,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if repo.Status().LoadedFromSnapshotFile {
+		t.Error("expect repo to be loaded from device-repository")
+		return
+	}
+	if _, err = os.Stat(snapshotFile); err != nil {
+		t.Error(err)
+		return
+	}
+	expected, err := repo.GetCharacteristic(temperature.Celsius)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	unavailable := httptest.NewServer(nil)
+	unavailable.Close()
+
+	t.Run("without snapshot", func(t *testing.T) {
+		_, err := conceptrepo.New(ctx, config.Config{
+			DeviceRepositoryUrl:        unavailable.URL,
+			ConceptRepoRefreshInterval: 42000,
+		}, mocks.MockAccess{}, mocks.ConceptRepoDefaults...)
+		if err == nil {
+			t.Error("expect error")
+		}
+	})
+
+	t.Run("with snapshot", func(t *testing.T) {
+		warm, err := conceptrepo.New(ctx, config.Config{
+			DeviceRepositoryUrl:        unavailable.URL,
+			ConceptRepoRefreshInterval: 42000,
+			ConceptRepoSnapshotFile:    snapshotFile,
+		}, mocks.MockAccess{}, mocks.ConceptRepoDefaults...)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !warm.Status().LoadedFromSnapshotFile {
+			t.Error("expect repo to be loaded from snapshot")
+		}
+		if warm.SnapshotAge() <= 0 {
+			t.Error("expect snapshot age")
+		}
+		actual, err := warm.GetCharacteristic(temperature.Celsius)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("\n%#v\n%#v\n", actual, expected)
+		}
+	})
+}
@@ -44,19 +44,25 @@ func TestConfigurablesPostRequest(t *testing.T) {
 		CharacteristicId: color.Rgb,
 		Values: []configurables.ConfigurableCharacteristicValue{
 			{
-				Label: "RGB r",
-				Path:  "r",
-				Value: "0",
+				Label:            "RGB r",
+				Path:             "r",
+				Value:            "0",
+				CharacteristicId: color.RgbR,
+				Type:             model.Integer,
 			},
 			{
-				Label: "RGB g",
-				Path:  "g",
-				Value: "0",
+				Label:            "RGB g",
+				Path:             "g",
+				Value:            "0",
+				CharacteristicId: color.RgbG,
+				Type:             model.Integer,
 			},
 			{
-				Label: "RGB b",
-				Path:  "b",
-				Value: "0",
+				Label:            "RGB b",
+				Path:             "b",
+				Value:            "0",
+				CharacteristicId: color.RgbB,
+				Type:             model.Integer,
 			},
 		},
 	})
@@ -78,19 +84,25 @@ func TestConfigurablesMockGetRequest(t *testing.T) {
 		CharacteristicId: color.Rgb,
 		Values: []configurables.ConfigurableCharacteristicValue{
 			{
-				Label: "RGB r",
-				Path:  "r",
-				Value: "0",
+				Label:            "RGB r",
+				Path:             "r",
+				Value:            "0",
+				CharacteristicId: color.RgbR,
+				Type:             model.Integer,
 			},
 			{
-				Label: "RGB g",
-				Path:  "g",
-				Value: "0",
+				Label:            "RGB g",
+				Path:             "g",
+				Value:            "0",
+				CharacteristicId: color.RgbG,
+				Type:             model.Integer,
 			},
 			{
-				Label: "RGB b",
-				Path:  "b",
-				Value: "0",
+				Label:            "RGB b",
+				Path:             "b",
+				Value:            "0",
+				CharacteristicId: color.RgbB,
+				Type:             model.Integer,
 			},
 		},
 	})
@@ -28,25 +28,25 @@ import (
 func ExampleConfigurableService_Find_configurablesShort() {
 	if !testing.Short() {
 		//skip
-		fmt.Println(`[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0"},{"label":"RGB g","path":"g","value":"0"},{"label":"RGB r","path":"r","value":"0"}]}]`)
+		fmt.Println(`[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0","characteristic_id":"urn:infai:ses:characteristic:590af9ef-3a5e-4edb-abab-177cb1320b17","type":"https://schema.org/Integer"},{"label":"RGB g","path":"g","value":"0","characteristic_id":"urn:infai:ses:characteristic:5ef27837-4aca-43ad-b8f6-4d95cf9ed99e","type":"https://schema.org/Integer"},{"label":"RGB r","path":"r","value":"0","characteristic_id":"urn:infai:ses:characteristic:dfe6be4a-650c-4411-8d87-062916b48951","type":"https://schema.org/Integer"}],"paths":[{"service_id":"s1","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43"},{"service_id":"s2","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"},{"service_id":"s2","path":"payload.color_2","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"}]}]`)
 	} else {
 		exampleFindConfigurables()
 	}
 
 	//output:
-	//[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0"},{"label":"RGB g","path":"g","value":"0"},{"label":"RGB r","path":"r","value":"0"}]}]
+	//[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0","characteristic_id":"urn:infai:ses:characteristic:590af9ef-3a5e-4edb-abab-177cb1320b17","type":"https://schema.org/Integer"},{"label":"RGB g","path":"g","value":"0","characteristic_id":"urn:infai:ses:characteristic:5ef27837-4aca-43ad-b8f6-4d95cf9ed99e","type":"https://schema.org/Integer"},{"label":"RGB r","path":"r","value":"0","characteristic_id":"urn:infai:ses:characteristic:dfe6be4a-650c-4411-8d87-062916b48951","type":"https://schema.org/Integer"}],"paths":[{"service_id":"s1","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43"},{"service_id":"s2","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"},{"service_id":"s2","path":"payload.color_2","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"}]}]
 }
 
 func ExampleConfigurableService_Find_configurablesLong() {
 	if testing.Short() {
 		//skip
-		fmt.Println(`[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0"},{"label":"RGB g","path":"g","value":"0"},{"label":"RGB r","path":"r","value":"0"}]}]`)
+		fmt.Println(`[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0","characteristic_id":"urn:infai:ses:characteristic:590af9ef-3a5e-4edb-abab-177cb1320b17","type":"https://schema.org/Integer"},{"label":"RGB g","path":"g","value":"0","characteristic_id":"urn:infai:ses:characteristic:5ef27837-4aca-43ad-b8f6-4d95cf9ed99e","type":"https://schema.org/Integer"},{"label":"RGB r","path":"r","value":"0","characteristic_id":"urn:infai:ses:characteristic:dfe6be4a-650c-4411-8d87-062916b48951","type":"https://schema.org/Integer"}],"paths":[{"service_id":"s1","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43"},{"service_id":"s2","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"},{"service_id":"s2","path":"payload.color_2","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"}]}]`)
 	} else {
 		exampleFindConfigurables()
 	}
 
 	//output:
-	//[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0"},{"label":"RGB g","path":"g","value":"0"},{"label":"RGB r","path":"r","value":"0"}]}]
+	//[{"characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43","values":[{"label":"RGB b","path":"b","value":"0","characteristic_id":"urn:infai:ses:characteristic:590af9ef-3a5e-4edb-abab-177cb1320b17","type":"https://schema.org/Integer"},{"label":"RGB g","path":"g","value":"0","characteristic_id":"urn:infai:ses:characteristic:5ef27837-4aca-43ad-b8f6-4d95cf9ed99e","type":"https://schema.org/Integer"},{"label":"RGB r","path":"r","value":"0","characteristic_id":"urn:infai:ses:characteristic:dfe6be4a-650c-4411-8d87-062916b48951","type":"https://schema.org/Integer"}],"paths":[{"service_id":"s1","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:5b4eea52-e8e5-4e80-9455-0382f81a1b43"},{"service_id":"s2","path":"payload.color","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"},{"service_id":"s2","path":"payload.color_2","characteristic_id":"urn:infai:ses:characteristic:0fc343ce-4627-4c88-b1e0-d3ed29754af8"}]}]
 }
 
 func exampleFindConfigurables() {
@@ -296,19 +296,25 @@ func TestFindIntersectingConfigurables1(t *testing.T) {
 		CharacteristicId: color.Rgb,
 		Values: []configurables.ConfigurableCharacteristicValue{
 			{
-				Label: "RGB r",
-				Path:  "r",
-				Value: "0",
+				Label:            "RGB r",
+				Path:             "r",
+				Value:            "0",
+				CharacteristicId: color.RgbR,
+				Type:             model.Integer,
 			},
 			{
-				Label: "RGB g",
-				Path:  "g",
-				Value: "0",
+				Label:            "RGB g",
+				Path:             "g",
+				Value:            "0",
+				CharacteristicId: color.RgbG,
+				Type:             model.Integer,
 			},
 			{
-				Label: "RGB b",
-				Path:  "b",
-				Value: "0",
+				Label:            "RGB b",
+				Path:             "b",
+				Value:            "0",
+				CharacteristicId: color.RgbB,
+				Type:             model.Integer,
 			},
 		},
 	})
@@ -348,3 +354,87 @@ func (this Assertions) ConfigurableListContains(list []configurables.Configurabl
 	}
 	this.Fatal("missing element in list", list, element)
 }
+
+func TestConfigurableCharacteristicMetadata(t *testing.T) {
+	segmentCleaning := "urn:infai:ses:characteristic:f48d7985-7ee7-4119-a791-bc16a953f440"
+	service := model.Service{
+		Id:         "s1",
+		ProtocolId: "p1",
+		Inputs: []model.Content{
+			{
+				Id: "c1",
+				ContentVariable: model.ContentVariable{
+					Id:   "c1.1",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:               "c1.1.1",
+							Name:             "temperature",
+							Type:             model.Float,
+							CharacteristicId: temperature.Celsius,
+						},
+						{
+							Id:               "c1.1.2",
+							Name:             "cleaning",
+							Type:             model.Structure,
+							CharacteristicId: segmentCleaning,
+						},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	t.Run("defaults and ranges", func(t *testing.T) {
+		configurablesList, err := TestFindConfigurables(temperature.Celsius, []model.Service{service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert := Assertions{t}
+		assert.ConfigurableListContains(configurablesList, configurables.Configurable{
+			CharacteristicId: segmentCleaning,
+			Values: []configurables.ConfigurableCharacteristicValue{
+				{
+					Label:            "Segment Cleaning Information (Valetudo) segment_ids 0",
+					Path:             "segment_ids.0",
+					Value:            "",
+					CharacteristicId: "urn:infai:ses:characteristic:802c79a9-c96b-4848-848c-bae29fb00375",
+					Type:             model.String,
+				},
+				{
+					Label:            "Segment Cleaning Information (Valetudo) iterations",
+					Path:             "iterations",
+					Value:            "1",
+					CharacteristicId: "urn:infai:ses:characteristic:63769613-47bd-4bb9-9624-083669ee6261",
+					Type:             model.Integer,
+					MinValue:         float64(1),
+					MaxValue:         float64(4),
+				},
+				{
+					Label:            "Segment Cleaning Information (Valetudo) customOrder",
+					Path:             "customOrder",
+					Value:            "true",
+					CharacteristicId: "urn:infai:ses:characteristic:aeacd820-8a9e-4a68-a4c6-412537bb8afb",
+					Type:             model.Boolean,
+				},
+			},
+		})
+	})
+
+	t.Run("display unit", func(t *testing.T) {
+		configurablesList, err := TestFindConfigurables(segmentCleaning, []model.Service{service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(configurablesList) != 1 || len(configurablesList[0].Values) != 1 {
+			t.Fatal(configurablesList)
+		}
+		value := configurablesList[0].Values[0]
+		if value.DisplayUnit != "°C" || value.MinValue != -273.15 || value.Type != model.Float || value.Value != "0" {
+			t.Errorf("%#v", value)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/util"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+type corsRequest struct {
+	method         string
+	origin         string
+	requestMethod  string
+	requestHeaders string
+}
+
+type corsExpectation struct {
+	code        int
+	origin      string
+	credentials string
+	methods     string
+	headers     string
+	exposed     string
+	maxAge      string
+}
+
+func testCors(conf config.Config, request corsRequest, expected corsExpectation) func(t *testing.T) {
+	return func(t *testing.T) {
+		handler := util.NewCors(conf, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		}))
+		req := httptest.NewRequest(request.method, "/v2/marshal", nil)
+		if request.origin != "" {
+			req.Header.Set("Origin", request.origin)
+		}
+		if request.requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", request.requestMethod)
+		}
+		if request.requestHeaders != "" {
+			req.Header.Set("Access-Control-Request-Headers", request.requestHeaders)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		actual := corsExpectation{
+			code:        res.Code,
+			origin:      res.Header().Get("Access-Control-Allow-Origin"),
+			credentials: res.Header().Get("Access-Control-Allow-Credentials"),
+			methods:     res.Header().Get("Access-Control-Allow-Methods"),
+			headers:     res.Header().Get("Access-Control-Allow-Headers"),
+			exposed:     res.Header().Get("Access-Control-Expose-Headers"),
+			maxAge:      res.Header().Get("Access-Control-Max-Age"),
+		}
+		if actual != expected {
+			t.Errorf("\n%#v\n%#v", actual, expected)
+		}
+	}
+}
+
+func TestCors(t *testing.T) {
+	defaultMethods := "GET, POST, PUT, DELETE, OPTIONS"
+	defaultHeaders := "Origin, X-Requested-With, Content-Type, Accept, Authorization"
+
+	t.Run("defaults", func(t *testing.T) {
+		conf := config.Config{}
+		t.Run("preflight", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://ui.example.com", requestMethod: "POST"},
+			corsExpectation{code: http.StatusForbidden}))
+		t.Run("request", testCors(conf,
+			corsRequest{method: http.MethodPost, origin: "https://ui.example.com"},
+			corsExpectation{code: http.StatusOK}))
+		t.Run("same origin", testCors(conf,
+			corsRequest{method: http.MethodPost},
+			corsExpectation{code: http.StatusOK}))
+	})
+
+	t.Run("exact origin", func(t *testing.T) {
+		conf := config.Config{
+			CorsAllowedOrigins:   []string{"https://ui.example.com"},
+			CorsExposedHeaders:   []string{"X-Output-Violations"},
+			CorsMaxAge:           600,
+			CorsAllowCredentials: true,
+		}
+		t.Run("preflight", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://ui.example.com", requestMethod: "POST", requestHeaders: "content-type, authorization"},
+			corsExpectation{code: http.StatusNoContent, origin: "https://ui.example.com", credentials: "true", methods: defaultMethods, headers: defaultHeaders, maxAge: "600"}))
+		t.Run("request", testCors(conf,
+			corsRequest{method: http.MethodPost, origin: "https://ui.example.com"},
+			corsExpectation{code: http.StatusOK, origin: "https://ui.example.com", credentials: "true", exposed: "X-Output-Violations"}))
+		t.Run("unknown origin preflight", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://evil.example.org", requestMethod: "POST"},
+			corsExpectation{code: http.StatusForbidden}))
+		t.Run("unknown origin request", testCors(conf,
+			corsRequest{method: http.MethodPost, origin: "https://evil.example.org"},
+			corsExpectation{code: http.StatusOK}))
+		t.Run("unknown method", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://ui.example.com", requestMethod: "PATCH"},
+			corsExpectation{code: http.StatusForbidden}))
+		t.Run("unknown header", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://ui.example.com", requestMethod: "POST", requestHeaders: "Content-Type, X-Custom"},
+			corsExpectation{code: http.StatusForbidden}))
+	})
+
+	t.Run("pattern", func(t *testing.T) {
+		conf := config.Config{
+			CorsAllowedOrigins: []string{"https://*.example.com"},
+			CorsAllowedMethods: []string{"POST"},
+			CorsAllowedHeaders: []string{"Content-Type"},
+		}
+		t.Run("match", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://app.example.com", requestMethod: "POST", requestHeaders: "Content-Type"},
+			corsExpectation{code: http.StatusNoContent, origin: "https://app.example.com", methods: "POST", headers: "Content-Type"}))
+		t.Run("suffix attack", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://app.example.com.evil.org", requestMethod: "POST"},
+			corsExpectation{code: http.StatusForbidden}))
+		t.Run("other scheme", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "http://app.example.com", requestMethod: "POST"},
+			corsExpectation{code: http.StatusForbidden}))
+		t.Run("method not configured", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://app.example.com", requestMethod: "GET"},
+			corsExpectation{code: http.StatusForbidden}))
+	})
+
+	t.Run("any origin", func(t *testing.T) {
+		conf := config.Config{
+			CorsAllowedOrigins:   []string{"*"},
+			CorsAllowCredentials: true,
+		}
+		t.Run("preflight without credentials", testCors(conf,
+			corsRequest{method: http.MethodOptions, origin: "https://ui.example.com", requestMethod: "GET"},
+			corsExpectation{code: http.StatusNoContent, origin: "*", methods: defaultMethods, headers: defaultHeaders}))
+		t.Run("request without credentials", testCors(conf,
+			corsRequest{method: http.MethodGet, origin: "https://ui.example.com"},
+			corsExpectation{code: http.StatusOK, origin: "*"}))
+	})
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/auth"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/devicerepository"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/signal"
+)
+
+func subjectToken(sub string, nonce string, roles ...string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub":          sub,
+		"nonce":        nonce,
+		"realm_access": map[string]interface{}{"roles": roles},
+	})
+	return "Bearer " + header + "." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+}
+
+func TestCallerScopedDeviceRepository(t *testing.T) {
+	requests := atomic.Int64{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		token, err := auth.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if token.Sub != "owner" && token.Sub != "" {
+			http.Error(writer, "access denied", http.StatusForbidden)
+			return
+		}
+		id := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+		switch {
+		case strings.HasPrefix(request.URL.Path, "/services/"):
+			json.NewEncoder(writer).Encode(model.Service{Id: id, Name: "owned service", ProtocolId: "p"})
+		case strings.HasPrefix(request.URL.Path, "/device-types/"):
+			json.NewEncoder(writer).Encode(model.DeviceType{Id: id, Name: "owned device-type"})
+		case strings.HasPrefix(request.URL.Path, "/protocols/"):
+			json.NewEncoder(writer).Encode(model.Protocol{Id: id, Name: "owned protocol"})
+		default:
+			http.Error(writer, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo, err := devicerepository.New(config.Config{DeviceRepositoryUrl: server.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := subjectToken("owner", "1", "user")
+	ownerSecondToken := subjectToken("owner", "2", "user")
+	other := subjectToken("other", "1", "user")
+
+	t.Run("owner loads service", func(t *testing.T) {
+		service, err, code := repo.GetServiceWithToken(owner, "s1")
+		if err != nil || code != http.StatusOK || service.Name != "owned service" {
+			t.Error(service, err, code)
+		}
+		if requests.Load() != 1 {
+			t.Error(requests.Load())
+		}
+	})
+
+	t.Run("same scope uses cache", func(t *testing.T) {
+		service, err, _ := repo.GetServiceWithToken(ownerSecondToken, "s1")
+		if err != nil || service.Name != "owned service" {
+			t.Error(service, err)
+		}
+		if requests.Load() != 1 {
+			t.Error(requests.Load())
+		}
+	})
+
+	t.Run("other user is not served from owner scope", func(t *testing.T) {
+		_, err, code := repo.GetServiceWithToken(other, "s1")
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+		if requests.Load() != 2 {
+			t.Error(requests.Load())
+		}
+	})
+
+	t.Run("different roles use a different scope", func(t *testing.T) {
+		_, err, _ := repo.GetServiceWithToken(subjectToken("owner", "3", "user", "admin"), "s1")
+		if err != nil {
+			t.Error(err)
+		}
+		if requests.Load() != 3 {
+			t.Error(requests.Load())
+		}
+	})
+
+	t.Run("tokens without subject are not cached", func(t *testing.T) {
+		before := requests.Load()
+		for i := 0; i < 2; i++ {
+			_, err, _ := repo.GetServiceWithToken(subjectToken("", "1", "user"), "s2")
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		if requests.Load() != before+2 {
+			t.Error(requests.Load() - before)
+		}
+	})
+
+	t.Run("device-types", func(t *testing.T) {
+		dt, err, _ := repo.GetDeviceTypeWithToken(owner, "dt1")
+		if err != nil || dt.Name != "owned device-type" {
+			t.Error(dt, err)
+		}
+		_, err, code := repo.GetDeviceTypeWithToken(other, "dt1")
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("protocols", func(t *testing.T) {
+		protocol, err := repo.GetProtocolWithToken(owner, "p1")
+		if err != nil || protocol.Name != "owned protocol" {
+			t.Error(protocol, err)
+		}
+		_, err = repo.GetProtocolWithToken(other, "p1")
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestCallerScopedDeviceInvalidation(t *testing.T) {
+	name := atomic.Value{}
+	name.Store("d1 before")
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !strings.HasPrefix(request.URL.Path, "/devices/") {
+			http.Error(writer, "not found", http.StatusNotFound)
+			return
+		}
+		id := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+		json.NewEncoder(writer).Encode(model.Device{Id: id, Name: name.Load().(string)})
+	}))
+	defer server.Close()
+
+	repo, err := devicerepository.New(config.Config{DeviceRepositoryUrl: server.URL}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := subjectToken("owner", "1", "user")
+
+	device, err, _ := repo.GetDeviceWithToken(owner, "d1")
+	if err != nil || device.Name != "d1 before" {
+		t.Error(device, err)
+	}
+	name.Store("d1 after")
+	device, err, _ = repo.GetDeviceWithToken(owner, "d1")
+	if err != nil || device.Name != "d1 before" {
+		t.Error("expected cached device", device, err)
+	}
+
+	signal.Known.DeviceCacheInvalidation.Pub("d2")
+	device, err, _ = repo.GetDeviceWithToken(owner, "d1")
+	if err != nil || device.Name != "d1 before" {
+		t.Error("expected cached device after invalidation of other device", device, err)
+	}
+
+	signal.Known.DeviceCacheInvalidation.Pub("d1")
+	device, err, _ = repo.GetDeviceWithToken(owner, "d1")
+	if err != nil || device.Name != "d1 after" {
+		t.Error("expected reloaded device after invalidation", device, err)
+	}
+}
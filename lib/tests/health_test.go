@@ -0,0 +1,145 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/conceptrepo"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
+)
+
+type emptyConceptRepo struct{}
+
+func (this emptyConceptRepo) Status() conceptrepo.Status {
+	return conceptrepo.Status{}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	t.Run("live", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/health/live")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Error(resp.StatusCode)
+		}
+	})
+	t.Run("ready", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/health/ready")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Error(resp.StatusCode)
+			return
+		}
+		report := health.Report{}
+		err = json.NewDecoder(resp.Body).Decode(&report)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !report.Ready || report.ConceptRepo == nil || report.ConceptRepo.Concepts == 0 || report.ConceptRepo.LoadedAt.IsZero() {
+			t.Errorf("%#v", report)
+		}
+	})
+}
+
+func TestHealthDependencies(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	reachable := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	}))
+	defer reachable.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer slow.Close()
+	tls := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	defer tls.Close()
+
+	h := health.New(config.Config{HealthCheckTimeout: 200}, emptyConceptRepo{})
+	h.Register("reachable", health.Reachable(http.DefaultClient, reachable.URL))
+	h.Register("failing", health.Reachable(http.DefaultClient, failing.URL))
+	h.Register("slow", health.Reachable(http.DefaultClient, slow.URL))
+	h.Register("disabled", health.Reachable(http.DefaultClient, ""))
+	h.Register("tls", health.Reachable(tls.Client(), tls.URL))
+	h.Register("tls with default client", health.Reachable(http.DefaultClient, tls.URL))
+	h.Register("state", health.NewState(errors.New("test error")).Check)
+
+	report := h.Ready(context.Background())
+	if report.Ready {
+		t.Error("expect not ready with empty concept-repo")
+	}
+	expected := map[string]string{
+		"reachable":               health.StatusOk,
+		"failing":                 health.StatusError,
+		"slow":                    health.StatusError,
+		"disabled":                health.StatusDisabled,
+		"tls":                     health.StatusOk,
+		"tls with default client": health.StatusError,
+		"state":                   health.StatusError,
+	}
+	if len(report.Dependencies) != len(expected) {
+		t.Errorf("%#v", report.Dependencies)
+		return
+	}
+	for _, dependency := range report.Dependencies {
+		if dependency.Status != expected[dependency.Name] {
+			t.Errorf("%#v", dependency)
+		}
+	}
+}
+
+func TestHealthStateReportError(t *testing.T) {
+	now := time.Now()
+	config.TimeNow = func() time.Time { return now }
+	defer func() { config.TimeNow = time.Now }()
+
+	state := health.NewState(nil)
+	if err := state.Check(context.Background()); err != nil {
+		t.Error(err)
+	}
+	state.ReportError(errors.New("temporary kafka error"))
+	if err := state.Check(context.Background()); err == nil || !strings.Contains(err.Error(), "temporary kafka error") {
+		t.Error(err)
+	}
+	now = now.Add(health.ErrorExpiration)
+	if err := state.Check(context.Background()); err != nil {
+		t.Error("expected recovered state after expiration", err)
+	}
+	state.Set(errors.New("permanent error"))
+	if err := state.Check(context.Background()); err == nil {
+		t.Error("expected set error")
+	}
+}
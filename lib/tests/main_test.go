@@ -35,6 +35,7 @@ import (
 	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
@@ -101,12 +102,12 @@ func setupMock(ctx context.Context, done *sync.WaitGroup) {
 	}
 	marshaller := marshaller.New(mocks.Converter{}, conceptRepo, mocks.DeviceRepo)
 	marshallerv2 := v2.New(config.Config{}, mocks.Converter{}, conceptRepo)
-	configurableService := configurables.New(conceptRepo)
+	configurableService := configurables.New(conceptRepo, marshallerv2)
 	TestMarshalInputs = marshaller.MarshalInputs
 	TestUnmarshalOutputs = marshaller.UnmarshalOutputs
 	TestFindConfigurables = configurableService.Find
 	done.Add(1)
-	server := httptest.NewServer(api.GetRouter(config.Config{Debug: true}, marshaller, marshallerv2, configurableService, mocks.DeviceRepo, nil, metrics.NewMetrics(config.Config{})))
+	server := httptest.NewServer(api.GetRouter(config.Config{Debug: true}, marshaller, marshallerv2, configurableService, mocks.DeviceRepo, nil, metrics.NewMetrics(config.Config{}), health.New(config.Config{}, conceptRepo), conceptRepo))
 	ServerUrl = server.URL
 	go func() {
 		<-ctx.Done()
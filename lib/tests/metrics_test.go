@@ -0,0 +1,219 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/api/util"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/converter"
+	"github.com/SENERGY-Platform/marshaller/lib/devicerepository"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+func scrapeMetrics(m *metrics.Metrics) string {
+	res := httptest.NewRecorder()
+	m.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return res.Body.String()
+}
+
+func expectMetrics(t *testing.T, m *metrics.Metrics, lines ...string) {
+	t.Helper()
+	scraped := scrapeMetrics(m)
+	for _, line := range lines {
+		if !strings.Contains(scraped, line+"\n") {
+			t.Error("missing metric:", line)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("requests", func(t *testing.T) {
+		m := metrics.NewMetrics(config.Config{})
+		router := api.NewRouter(config.Config{}, nil, nil, nil, mocks.DeviceRepo, nil, m, nil, nil)
+		handler := util.NewRequestMetrics(m, router, router)
+		for _, path := range []string{"/health/live", "/health/live", "/characteristic-paths/unknown-service/unknown-characteristic", "/unknown"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v2/configurables", strings.NewReader("not json")))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/marshal/marshal/x", strings.NewReader("not json")))
+		expectMetrics(t, m,
+			`marshaller_requests_total{code="200",endpoint="/health/live",method="GET",status="success"} 2`,
+			`marshaller_requests_total{code="500",endpoint="/characteristic-paths/:serviceId/:characteristicId",method="GET",status="server_error"} 1`,
+			`marshaller_requests_total{code="404",endpoint="unknown",method="GET",status="client_error"} 1`,
+			`marshaller_requests_total{code="400",endpoint="/v2/configurables",method="POST",status="client_error"} 1`,
+			`marshaller_requests_total{code="400",endpoint="/marshal/:serviceId/:characteristicId",method="POST",status="client_error"} 1`,
+		)
+	})
+
+	t.Run("converter calls", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if strings.HasSuffix(request.URL.Path, "/fail") {
+				http.Error(writer, "unknown conversion", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(writer).Encode(42)
+		}))
+		defer server.Close()
+		m := metrics.NewMetrics(config.Config{})
+		c := converter.New(config.Config{ConverterUrl: server.URL}, config.StaticToken(""))
+		c.SetMetrics(m)
+		_, err := c.Cast(1, "a", "b")
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = c.Cast(1, "a", "fail")
+		if err == nil {
+			t.Error("expected error")
+		}
+		_, err = c.Cast(1, "a", "a") //no call
+		if err != nil {
+			t.Error(err)
+		}
+		expectMetrics(t, m,
+			`marshaller_converter_call_duration_seconds_count{from="a",status="success",to="b"} 1`,
+			`marshaller_converter_call_duration_seconds_count{from="a",status="error",to="fail"} 1`,
+		)
+		if strings.Contains(scrapeMetrics(m), `to="a"`) {
+			t.Error("unexpected metric for identical characteristics")
+		}
+	})
+
+	t.Run("device-repository cache", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			json.NewEncoder(writer).Encode(model.DeviceType{Id: "dt1"})
+		}))
+		defer server.Close()
+		repo, err := devicerepository.New(config.Config{DeviceRepositoryUrl: server.URL}, config.StaticToken(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := metrics.NewMetrics(config.Config{})
+		m.ObserveDeviceRepository(repo)
+		for i := 0; i < 3; i++ {
+			_, err, _ = repo.GetDeviceType("dt1")
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		expectMetrics(t, m,
+			`marshaller_device_repository_cache_requests_total{kind="device-type",result="hit"} 2`,
+			`marshaller_device_repository_cache_requests_total{kind="device-type",result="miss"} 1`,
+			`marshaller_device_repository_cache_requests_total{kind="service",result="miss"} 0`,
+		)
+	})
+
+	t.Run("concept-repo", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		repo, err := mocks.NewMockConceptRepo(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := metrics.NewMetrics(config.Config{})
+		m.ObserveConceptRepo(repo)
+		dump := repo.Dump()
+		sizes := repo.Sizes()
+		if sizes.Concepts != len(dump.Concepts) || sizes.Characteristics != len(dump.Characteristics) || sizes.Functions != len(dump.FunctionToConcept) {
+			t.Error(sizes)
+		}
+		scraped := scrapeMetrics(m)
+		for _, metric := range []string{"marshaller_concept_repo_last_full_load_duration_seconds ", "marshaller_concept_repo_snapshot_age_seconds "} {
+			if !strings.Contains(scraped, metric) {
+				t.Error("missing metric:", metric)
+			}
+		}
+		expectMetrics(t, m,
+			`marshaller_concept_repo_size{kind="concepts"} `+strconv.Itoa(sizes.Concepts),
+			`marshaller_concept_repo_size{kind="characteristics"} `+strconv.Itoa(sizes.Characteristics),
+			`marshaller_concept_repo_size{kind="functions"} `+strconv.Itoa(sizes.Functions),
+		)
+	})
+}
+
+func TestMetricsLabelCardinality(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/v2/marshal", nil)
+	request.RemoteAddr = "192.0.2.1:1234"
+	marshal := func(m *metrics.Metrics, serviceId string, functionIds ...string) {
+		msg := messages.MarshallingV2Request{Service: model.Service{Id: serviceId}}
+		for _, functionId := range functionIds {
+			msg.Data = append(msg.Data, model.MarshallingV2RequestData{FunctionId: functionId})
+		}
+		m.LogMarshallingRequest(request, "/v2/marshal", msg, time.Millisecond)
+	}
+
+	t.Run("label set", func(t *testing.T) {
+		m := metrics.NewMetrics(config.Config{MetricsLabels: []string{metrics.LabelEndpoint, metrics.LabelServiceId}})
+		marshal(m, "s1", "f1")
+		expectMetrics(t, m, `marshaller_marshalling_request_handling_duration_count{endpoint="/v2/marshal",service_id="s1"} 1`)
+	})
+
+	t.Run("allow-lists", func(t *testing.T) {
+		m := metrics.NewMetrics(config.Config{
+			MetricsLabels:              []string{metrics.LabelServiceId, metrics.LabelFunctionIds},
+			MetricsServiceIdAllowList:  []string{"s1"},
+			MetricsFunctionIdAllowList: []string{"f1"},
+		})
+		marshal(m, "s1", "f1")
+		marshal(m, "s2", "f2", "f1", "f3")
+		marshal(m, "s3", "f4")
+		expectMetrics(t, m,
+			`marshaller_marshalling_request_handling_duration_count{function_ids="f1",service_id="s1"} 1`,
+			`marshaller_marshalling_request_handling_duration_count{function_ids="f1,other",service_id="other"} 1`,
+			`marshaller_marshalling_request_handling_duration_count{function_ids="other",service_id="other"} 1`,
+		)
+	})
+
+	t.Run("max label values", func(t *testing.T) {
+		m := metrics.NewMetrics(config.Config{
+			MetricsLabels:         []string{metrics.LabelCallSource, metrics.LabelServiceId, metrics.LabelFunctionIds},
+			MetricsMaxLabelValues: 2,
+		})
+		marshal(m, "s1", "f1")
+		marshal(m, "s2", "f2", "f1")
+		marshal(m, "s3", "f3")
+		marshal(m, "s1", "f1")
+		scraped := scrapeMetrics(m)
+		if strings.Contains(scraped, `service_id="s3"`) || strings.Contains(scraped, `function_ids="f3"`) {
+			t.Error("unexpected label value over limit")
+		}
+		if !strings.Contains(scraped, `function_ids="f1,f2"`) {
+			t.Error("expected sorted function ids")
+		}
+		if strings.Contains(scraped, "1234") {
+			t.Error("call source should not contain the remote port")
+		}
+		if !strings.Contains(scraped, `function_ids="f1",service_id="s1"} 2`) {
+			t.Error("missing known label values")
+		}
+		if !strings.Contains(scraped, `function_ids="other",service_id="other"} 1`) {
+			t.Error("missing other label values")
+		}
+	})
+}
@@ -41,40 +41,76 @@ func NilCallback[T any](T) error {
 }
 
 func NewMockConceptRepo(ctx context.Context) (*conceptrepo.ConceptRepo, error) {
+	return NewMockConceptRepoWithConfig(ctx, config.Config{
+		ConceptRepoRefreshInterval: 42000,
+		LogLevel:                   "debug",
+	})
+}
+
+// NewMockConceptRepoWithConfig uses conf with DeviceRepositoryUrl set to a mocked device-repository
+func NewMockConceptRepoWithConfig(ctx context.Context, conf config.Config) (*conceptrepo.ConceptRepo, error) {
+	repo, _, err := NewMockConceptRepoWithUpdate(ctx, conf)
+	return repo, err
+}
+
+// MockDeviceRepoUpdate changes the state of the mocked device-repository used by a mocked concept-repo
+type MockDeviceRepoUpdate struct {
+	SetConcept        func(concept model.Concept) error
+	SetCharacteristic func(characteristic model.Characteristic) error
+	SetFunction       func(function model.Function) error
+}
+
+// NewMockConceptRepoWithUpdate uses ConceptRepoDefaults if no defaults are given
+func NewMockConceptRepoWithUpdate(ctx context.Context, conf config.Config, defaults ...conceptrepo.ConceptRepoDefault) (repo *conceptrepo.ConceptRepo, update MockDeviceRepoUpdate, err error) {
+	if len(defaults) == 0 {
+		defaults = ConceptRepoDefaults
+	}
 	functions, err := testdata.GetFunctions()
 	if err != nil {
-		return nil, err
+		return nil, update, err
 	}
 	concepts, err := testdata.GetConcepts()
 	if err != nil {
-		return nil, err
+		return nil, update, err
 	}
 	characteristicsList, err := testdata.GetCharacteristics()
 	if err != nil {
-		return nil, err
+		return nil, update, err
 	}
 
 	c, db, err := client.NewTestClient()
 	if err != nil {
-		return nil, err
+		return nil, update, err
+	}
+
+	update = MockDeviceRepoUpdate{
+		SetConcept: func(concept model.Concept) error {
+			return db.SetConcept(ctx, concept, NilCallback)
+		},
+		SetCharacteristic: func(characteristic model.Characteristic) error {
+			return db.SetCharacteristic(ctx, characteristic, NilCallback)
+		},
+		SetFunction: func(function model.Function) error {
+			return db.SetFunction(ctx, function, NilCallback)
+		},
 	}
 
 	for _, function := range functions {
-		err = db.SetFunction(ctx, function, NilCallback)
+		err = update.SetFunction(function)
 		if err != nil {
-			return nil, err
+			return nil, update, err
 		}
 	}
 	for _, concept := range concepts {
-		err = db.SetConcept(ctx, concept, NilCallback)
+		err = update.SetConcept(concept)
 		if err != nil {
-			return nil, err
+			return nil, update, err
 		}
 	}
 	for _, characteristic := range characteristicsList {
-		err = db.SetCharacteristic(ctx, characteristic, NilCallback)
+		err = update.SetCharacteristic(characteristic)
 		if err != nil {
-			return nil, err
+			return nil, update, err
 		}
 	}
 
@@ -85,80 +121,79 @@ func NewMockConceptRepo(ctx context.Context) (*conceptrepo.ConceptRepo, error) {
 		server.Close()
 	}()
 
-	config := config.Config{
-		DeviceRepositoryUrl:        server.URL,
-		ConceptRepoRefreshInterval: 42000,
-		LogLevel:                   "debug",
-	}
-	return conceptrepo.New(ctx, config, MockAccess{},
-		conceptrepo.ConceptRepoDefault{
-			Concept: model.NullConcept,
-			Characteristics: []model.Characteristic{
-				model.NullCharacteristic,
-			},
+	conf.DeviceRepositoryUrl = server.URL
+	repo, err = conceptrepo.New(ctx, conf, MockAccess{}, defaults...)
+	return repo, update, err
+}
+
+var ConceptRepoDefaults = []conceptrepo.ConceptRepoDefault{
+	{
+		Concept: model.NullConcept,
+		Characteristics: []model.Characteristic{
+			model.NullCharacteristic,
 		},
-		conceptrepo.ConceptRepoDefault{
-			Concept: model.Concept{Id: exampleColor, Name: "example", BaseCharacteristicId: exampleRgb},
-			Characteristics: []model.Characteristic{
-				{
-					Id:   exampleRgb,
-					Name: "rgb",
-					Type: model.Structure,
-					SubCharacteristics: []model.Characteristic{
-						{Id: exampleRgb + ".r", Name: "r", Type: model.Integer},
-						{Id: exampleRgb + ".g", Name: "g", Type: model.Integer},
-						{Id: exampleRgb + ".b", Name: "b", Type: model.Integer},
-					},
-				},
-				{
-					Id:   exampleHex,
-					Name: "hex",
-					Type: model.String,
+	},
+	{
+		Concept: model.Concept{Id: exampleColor, Name: "example", BaseCharacteristicId: exampleRgb},
+		Characteristics: []model.Characteristic{
+			{
+				Id:   exampleRgb,
+				Name: "rgb",
+				Type: model.Structure,
+				SubCharacteristics: []model.Characteristic{
+					{Id: exampleRgb + ".r", Name: "r", Type: model.Integer},
+					{Id: exampleRgb + ".g", Name: "g", Type: model.Integer},
+					{Id: exampleRgb + ".b", Name: "b", Type: model.Integer},
 				},
 			},
+			{
+				Id:   exampleHex,
+				Name: "hex",
+				Type: model.String,
+			},
 		},
-		conceptrepo.ConceptRepoDefault{
-			Concept: model.Concept{Id: exampleBrightness, Name: "example-bri"},
-			Characteristics: []model.Characteristic{
-				{
-					Id:   exampleLux,
-					Name: "lux",
-					Type: model.Integer,
-				},
+	},
+	{
+		Concept: model.Concept{Id: exampleBrightness, Name: "example-bri"},
+		Characteristics: []model.Characteristic{
+			{
+				Id:   exampleLux,
+				Name: "lux",
+				Type: model.Integer,
 			},
 		},
-		conceptrepo.ConceptRepoDefault{
-			Concept: model.Concept{Id: "side-celsius", Name: "side-celsius"},
-			Characteristics: []model.Characteristic{
-				{
-					Id:   characteristics.Celsius,
-					Name: "celsius",
-					Type: model.Integer,
-				},
-				{
-					Id:   "side-celsius-foo",
-					Name: "side-celsius-foo",
-					Type: model.Integer,
-				},
+	},
+	{
+		Concept: model.Concept{Id: "side-celsius", Name: "side-celsius"},
+		Characteristics: []model.Characteristic{
+			{
+				Id:   characteristics.Celsius,
+				Name: "celsius",
+				Type: model.Integer,
+			},
+			{
+				Id:   "side-celsius-foo",
+				Name: "side-celsius-foo",
+				Type: model.Integer,
 			},
 		},
+	},
 
-		conceptrepo.ConceptRepoDefault{
-			Concept: model.Concept{Id: "side-kelvin", Name: "side-kelvin"},
-			Characteristics: []model.Characteristic{
-				{
-					Id:   characteristics.Kelvin,
-					Name: "kelvin",
-					Type: model.Integer,
-				},
-				{
-					Id:   "side-kelvin-foo",
-					Name: "side-kelvin-foo",
-					Type: model.Integer,
-				},
+	{
+		Concept: model.Concept{Id: "side-kelvin", Name: "side-kelvin"},
+		Characteristics: []model.Characteristic{
+			{
+				Id:   characteristics.Kelvin,
+				Name: "kelvin",
+				Type: model.Integer,
+			},
+			{
+				Id:   "side-kelvin-foo",
+				Name: "side-kelvin-foo",
+				Type: model.Integer,
 			},
 		},
-	)
+	},
 }
 
 type MockAccess struct{}
@@ -28,6 +28,7 @@ var DeviceRepo = (&DeviceRepoStruct{}).Init()
 
 type DeviceRepoStruct struct {
 	deviceTypes map[string]model.DeviceType
+	devices     map[string]model.Device
 	services    map[string]model.Service
 	protocols   map[string]model.Protocol
 	aspectnodes map[string]model.AspectNode
@@ -37,6 +38,7 @@ func (this *DeviceRepoStruct) Init() *DeviceRepoStruct {
 	this.services = map[string]model.Service{}
 	this.protocols = map[string]model.Protocol{}
 	this.deviceTypes = map[string]model.DeviceType{}
+	this.devices = map[string]model.Device{}
 	this.aspectnodes = map[string]model.AspectNode{}
 
 	aspects, err := testdata.GetAspectNodes()
@@ -63,6 +65,19 @@ func (this *DeviceRepoStruct) SetDeviceType(dt model.DeviceType) *DeviceRepoStru
 	return this
 }
 
+func (this *DeviceRepoStruct) GetDevice(id string) (result model.Device, err error, code int) {
+	if device, ok := this.devices[id]; ok {
+		return device, nil, 200
+	} else {
+		return device, errors.New("not found"), 404
+	}
+}
+
+func (this *DeviceRepoStruct) SetDevice(device model.Device) *DeviceRepoStruct {
+	this.devices[device.Id] = device
+	return this
+}
+
 func (this *DeviceRepoStruct) GetService(serviceId string) (model.Service, error) {
 	if service, ok := this.services[serviceId]; ok {
 		return service, nil
@@ -111,6 +126,22 @@ func (this *DeviceRepoStruct) GetServiceWithErrCode(serviceId string) (result mo
 	return
 }
 
+func (this *DeviceRepoStruct) GetServiceWithToken(token string, serviceId string) (result model.Service, err error, code int) {
+	return this.GetServiceWithErrCode(serviceId)
+}
+
+func (this *DeviceRepoStruct) GetProtocolWithToken(token string, id string) (model.Protocol, error) {
+	return this.GetProtocol(id)
+}
+
+func (this *DeviceRepoStruct) GetDeviceTypeWithToken(token string, id string) (model.DeviceType, error, int) {
+	return this.GetDeviceType(id)
+}
+
+func (this *DeviceRepoStruct) GetDeviceWithToken(token string, id string) (model.Device, error, int) {
+	return this.GetDevice(id)
+}
+
 func (this *DeviceRepoStruct) GetAspectNode(id string) (result model.AspectNode, err error) {
 	if aspect, ok := this.aspectnodes[id]; ok {
 		return aspect, nil
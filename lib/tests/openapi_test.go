@@ -0,0 +1,114 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+)
+
+func TestOpenApi(t *testing.T) {
+	resp, err := http.Get(ServerUrl + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatal(resp.StatusCode)
+	}
+	document := map[string]interface{}{}
+	err = json.NewDecoder(resp.Body).Decode(&document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := document["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		t.Error(document["openapi"])
+	}
+
+	documented := map[string]bool{}
+	for path, operations := range document["paths"].(map[string]interface{}) {
+		for method := range operations.(map[string]interface{}) {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	//AuthJwksUrl enables the admin endpoints; the keys are only loaded on token verification
+	router := api.NewRouter(config.Config{AuthJwksUrl: "unused-jwks.json"}, nil, nil, nil, nil, nil, metrics.NewMetrics(config.Config{}), nil, nil)
+	registered := map[string]bool{}
+	for _, route := range openApiRoutes(router.Routes()) {
+		registered[route] = true
+		if !documented[route] {
+			t.Error("route is not described in openapi document:", route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			t.Error("openapi document describes unknown route:", route)
+		}
+	}
+
+	t.Run("schema references", func(t *testing.T) {
+		schemas := document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		for _, ref := range findSchemaRefs(document) {
+			if _, ok := schemas[strings.TrimPrefix(ref, "#/components/schemas/")]; !ok {
+				t.Error("unknown schema reference", ref)
+			}
+		}
+		if _, ok := schemas["messages.MarshallingV2Request"]; !ok {
+			t.Error("missing messages.MarshallingV2Request schema")
+		}
+	})
+}
+
+// openApiRoutes converts routes to the openapi path syntax (e.g. 'GET /v2/marshal/{serviceId}')
+func openApiRoutes(routes []api.Route) (result []string) {
+	for _, route := range routes {
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+				segments[i] = "{" + segment[1:] + "}"
+			}
+		}
+		result = append(result, route.Method+" "+strings.Join(segments, "/"))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func findSchemaRefs(value interface{}) (result []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, sub := range v {
+			if ref, ok := sub.(string); ok && key == "$ref" {
+				result = append(result, ref)
+			}
+			result = append(result, findSchemaRefs(sub)...)
+		}
+	case []interface{}:
+		for _, sub := range v {
+			result = append(result, findSchemaRefs(sub)...)
+		}
+	}
+	return result
+}
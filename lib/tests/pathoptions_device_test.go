@@ -0,0 +1,160 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+	"github.com/SENERGY-Platform/models/go/models"
+)
+
+func TestDevicePathOptions(t *testing.T) {
+	getTemperature := "urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b"
+	celsius := temperature.Celsius
+	deviceTypeId := "TestDevicePathOptions"
+	serviceId := "TestDevicePathOptions.service"
+	outsideDeviceId := "TestDevicePathOptions.outside"
+	defaultDeviceId := "TestDevicePathOptions.default"
+
+	mocks.DeviceRepo.SetDeviceType(model.DeviceType{
+		Id:   deviceTypeId,
+		Name: deviceTypeId,
+		Services: []model.Service{
+			{
+				Id:      serviceId,
+				LocalId: "sensor",
+				Name:    serviceId,
+				Outputs: []model.Content{
+					{
+						ContentVariable: model.ContentVariable{
+							Name: "values",
+							Type: model.Structure,
+							SubContentVariables: []model.ContentVariable{
+								{Name: "temperature", Type: model.Float, CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "air"},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	mocks.DeviceRepo.SetDevice(model.Device{
+		Id:           outsideDeviceId,
+		LocalId:      "outside-local",
+		Name:         "outside sensor",
+		DeviceTypeId: deviceTypeId,
+		Attributes: []models.Attribute{
+			{Key: model.AspectOverrideAttributePrefix + "sensor/values.temperature", Value: "outside_air"},
+			{Key: model.AspectOverrideAttributePrefix + "unknown/values.temperature", Value: "inside_air"},
+		},
+	})
+	mocks.DeviceRepo.SetDevice(model.Device{
+		Id:           defaultDeviceId,
+		LocalId:      "default-local",
+		Name:         "default sensor",
+		DeviceTypeId: deviceTypeId,
+	})
+
+	t.Run("v2 devices with override", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceTypeIds: []string{deviceTypeId},
+		DeviceIds:     []string{outsideDeviceId, defaultDeviceId},
+		FunctionId:    getTemperature,
+		AspectId:      "outside_air",
+	}, map[string][]v2.PathOption{
+		deviceTypeId: {},
+		outsideDeviceId: {
+			{ServiceId: serviceId, Path: "values.temperature", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "outside_air", DeviceId: outsideDeviceId, DeviceName: "outside sensor", DeviceLocalId: "outside-local"},
+		},
+		defaultDeviceId: {},
+	}))
+
+	t.Run("v2 devices without override", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceIds:  []string{outsideDeviceId, defaultDeviceId},
+		FunctionId: getTemperature,
+		AspectId:   "air",
+	}, map[string][]v2.PathOption{
+		outsideDeviceId: {
+			{ServiceId: serviceId, Path: "values.temperature", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "outside_air", AspectDistance: 1, DeviceId: outsideDeviceId, DeviceName: "outside sensor", DeviceLocalId: "outside-local"},
+		},
+		defaultDeviceId: {
+			{ServiceId: serviceId, Path: "values.temperature", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "air", DeviceId: defaultDeviceId, DeviceName: "default sensor", DeviceLocalId: "default-local"},
+		},
+	}))
+
+	t.Run("legacy devices", func(t *testing.T) {
+		result := map[string][]marshaller.PathOptionsResultElement{}
+		testPostJson(t, "/query/path-options", messages.PathOptionsQuery{
+			DeviceIds:              []string{outsideDeviceId, defaultDeviceId},
+			FunctionId:             getTemperature,
+			AspectId:               "outside_air",
+			CharacteristicIdFilter: []string{celsius},
+		}, &result)
+		if len(result) != 2 || len(result[defaultDeviceId]) != 0 || len(result[outsideDeviceId]) != 1 {
+			t.Error(result)
+			return
+		}
+		element := result[outsideDeviceId][0]
+		if element.ServiceId != serviceId || element.DeviceName != "outside sensor" || element.DeviceLocalId != "outside-local" || element.PathToCharacteristicId["value.values.temperature"] != celsius {
+			t.Error(element)
+		}
+	})
+
+	t.Run("v2 get", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/v2/path-options?device-ids=" + outsideDeviceId + "&function-id=" + url.QueryEscape(getTemperature) + "&aspect-id=outside_air")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		result := map[string][]v2.PathOption{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 1 || len(result[outsideDeviceId]) != 1 || result[outsideDeviceId][0].DeviceLocalId != "outside-local" {
+			t.Error(result)
+		}
+	})
+
+	t.Run("unknown device", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/v2/path-options?device-ids=TestDevicePathOptions.unknown")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Error(resp.StatusCode)
+		}
+	})
+
+	t.Run("device-type unchanged", func(t *testing.T) {
+		dt, _, _ := mocks.DeviceRepo.GetDeviceType(deviceTypeId)
+		if dt.Services[0].Outputs[0].ContentVariable.SubContentVariables[0].AspectId != "air" {
+			t.Error(dt)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+func TestPathOptionsDirectionAndV2(t *testing.T) {
+	getTemperature := "urn:infai:ses:measuring-function:f2769eb9-b6ad-4f7e-bd28-e4ea043d2f8b"
+	setTemperature := "urn:infai:ses:controlling-function:99240d90-02dd-4d4f-a47c-069cfe77629c"
+	celsius := temperature.Celsius
+	deviceTypeId := "TestPathOptionsDirectionAndV2"
+	serviceId := "TestPathOptionsDirectionAndV2.service"
+
+	mocks.DeviceRepo.SetDeviceType(model.DeviceType{
+		Id:   deviceTypeId,
+		Name: deviceTypeId,
+		Services: []model.Service{
+			{
+				Id:      serviceId,
+				LocalId: serviceId,
+				Name:    serviceId,
+				Inputs: []model.Content{
+					{
+						ContentVariable: model.ContentVariable{
+							Name: "target",
+							Type: model.Structure,
+							SubContentVariables: []model.ContentVariable{
+								{Name: "celsius", Type: model.Float, CharacteristicId: celsius, FunctionId: setTemperature, AspectId: "inside_air"},
+								{Name: "duration", Type: model.Integer},
+							},
+						},
+					},
+				},
+				Outputs: []model.Content{
+					{
+						ContentVariable: model.ContentVariable{
+							Name: "values",
+							Type: model.Structure,
+							SubContentVariables: []model.ContentVariable{
+								{Name: "outside", Type: model.Float, CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "outside_air"},
+								{Name: "inside", Type: model.Float, CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "inside_air"},
+								{Name: "average", Type: model.Float, CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "air"},
+								{Name: "power", Type: model.Float, FunctionId: "urn:infai:ses:measuring-function:other", AspectId: "today"},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	t.Run("legacy inputs", testPathOptionsWithDirection(
+		[]string{deviceTypeId}, setTemperature, "air", []string{celsius}, model.DirectionInputs,
+		map[string][]marshaller.PathOptionsResultElement{
+			deviceTypeId: {
+				{
+					ServiceId:              serviceId,
+					JsonPath:               []string{"target.celsius"},
+					PathToCharacteristicId: map[string]string{"target.celsius": celsius},
+				},
+			},
+		}))
+
+	t.Run("legacy unknown direction", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/path-options?device-type-ids=" + deviceTypeId + "&characteristic-filter=" + url.QueryEscape(celsius) + "&direction=foo")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Error(resp.StatusCode)
+		}
+	})
+
+	t.Run("v2 outputs with aspect distance", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceTypeIds: []string{deviceTypeId},
+		FunctionId:    getTemperature,
+		AspectId:      "air",
+	}, map[string][]v2.PathOption{
+		deviceTypeId: {
+			{ServiceId: serviceId, Path: "values.average", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "air", AspectDistance: 0},
+			{ServiceId: serviceId, Path: "values.inside", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "inside_air", AspectDistance: 1},
+			{ServiceId: serviceId, Path: "values.outside", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "outside_air", AspectDistance: 1},
+		},
+	}))
+
+	t.Run("v2 outputs of sub aspect", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceTypeIds: []string{deviceTypeId},
+		FunctionId:    getTemperature,
+		AspectId:      "inside_air",
+	}, map[string][]v2.PathOption{
+		deviceTypeId: {
+			{ServiceId: serviceId, Path: "values.inside", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "inside_air", AspectDistance: 0},
+		},
+	}))
+
+	t.Run("v2 inputs", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceTypeIds: []string{deviceTypeId},
+		FunctionId:    setTemperature,
+		Direction:     model.DirectionInputs,
+	}, map[string][]v2.PathOption{
+		deviceTypeId: {
+			{ServiceId: serviceId, Path: "target.celsius", CharacteristicId: celsius, FunctionId: setTemperature, AspectId: "inside_air", AspectDistance: 0},
+		},
+	}))
+
+	t.Run("v2 without function", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceTypeIds: []string{deviceTypeId},
+	}, map[string][]v2.PathOption{
+		deviceTypeId: {
+			{ServiceId: serviceId, Path: "values.average", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "air", AspectDistance: 0},
+			{ServiceId: serviceId, Path: "values.inside", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "inside_air", AspectDistance: 0},
+			{ServiceId: serviceId, Path: "values.outside", CharacteristicId: celsius, FunctionId: getTemperature, AspectId: "outside_air", AspectDistance: 0},
+			{ServiceId: serviceId, Path: "values.power", FunctionId: "urn:infai:ses:measuring-function:other", AspectId: "today", AspectDistance: 0},
+		},
+	}))
+
+	t.Run("v2 characteristic filter", testPathOptionsV2(messages.PathOptionsV2Query{
+		DeviceTypeIds:          []string{deviceTypeId},
+		FunctionId:             getTemperature,
+		CharacteristicIdFilter: []string{"urn:infai:ses:characteristic:unknown"},
+	}, map[string][]v2.PathOption{
+		deviceTypeId: {},
+	}))
+
+	t.Run("v2 get", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/v2/path-options?device-type-ids=" + deviceTypeId + "&function-id=" + url.QueryEscape(setTemperature) + "&direction=inputs")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		result := map[string][]v2.PathOption{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result[deviceTypeId]) != 1 || result[deviceTypeId][0].Path != "target.celsius" {
+			t.Error(result)
+		}
+	})
+
+	t.Run("v2 unknown direction", func(t *testing.T) {
+		resp, err := http.Get(ServerUrl + "/v2/path-options?device-type-ids=" + deviceTypeId + "&direction=foo")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Error(resp.StatusCode)
+		}
+	})
+}
+
+func testPathOptionsWithDirection(deviceTypes []string, functionId string, aspect string, characteristicsFilter []string, direction string, expectedResult map[string][]marshaller.PathOptionsResultElement) func(t *testing.T) {
+	return func(t *testing.T) {
+		result := map[string][]marshaller.PathOptionsResultElement{}
+		testPostJson(t, "/query/path-options", messages.PathOptionsQuery{
+			DeviceTypeIds:          deviceTypes,
+			FunctionId:             functionId,
+			AspectId:               aspect,
+			CharacteristicIdFilter: characteristicsFilter,
+			Direction:              direction,
+		}, &result)
+		if !reflect.DeepEqual(expectedResult, result) {
+			resultJson, _ := json.Marshal(result)
+			expectedJson, _ := json.Marshal(expectedResult)
+			t.Error(string(resultJson), "\n", string(expectedJson))
+		}
+	}
+}
+
+func testPathOptionsV2(query messages.PathOptionsV2Query, expectedResult map[string][]v2.PathOption) func(t *testing.T) {
+	return func(t *testing.T) {
+		result := map[string][]v2.PathOption{}
+		testPostJson(t, "/v2/query/path-options", query, &result)
+		if !reflect.DeepEqual(expectedResult, result) {
+			resultJson, _ := json.Marshal(result)
+			expectedJson, _ := json.Marshal(expectedResult)
+			t.Error(string(resultJson), "\n", string(expectedJson))
+		}
+	}
+}
+
+func testPostJson(t *testing.T, path string, body interface{}, result interface{}) {
+	buff := bytes.Buffer{}
+	err := json.NewEncoder(&buff).Encode(body)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	resp, err := http.Post(ServerUrl+path, "application/json", &buff)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		temp, _ := io.ReadAll(resp.Body)
+		t.Error(resp.StatusCode, string(temp))
+		return
+	}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/devicerepository"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+)
+
+type testTokenEndpoint struct {
+	server        *httptest.Server
+	paths         []string
+	grants        map[string]int
+	mux           sync.Mutex
+	expiresIn     float64
+	requestsTotal atomic.Int64
+}
+
+func newTestTokenEndpoint(expiresIn float64) *testTokenEndpoint {
+	result := &testTokenEndpoint{grants: map[string]int{}, expiresIn: expiresIn}
+	result.server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		result.requestsTotal.Add(1)
+		time.Sleep(10 * time.Millisecond) //give concurrent callers the chance to overlap
+		err := request.ParseForm()
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result.mux.Lock()
+		result.paths = append(result.paths, request.URL.Path)
+		result.grants[request.PostForm.Get("grant_type")]++
+		result.mux.Unlock()
+		json.NewEncoder(writer).Encode(config.OpenidToken{
+			AccessToken:      "token-" + request.PostForm.Get("grant_type"),
+			ExpiresIn:        result.expiresIn,
+			RefreshExpiresIn: 3600,
+			RefreshToken:     "refresh",
+		})
+	}))
+	return result
+}
+
+func TestTokenProviders(t *testing.T) {
+	t.Run("oidc realm", func(t *testing.T) {
+		endpoint := newTestTokenEndpoint(3600)
+		defer endpoint.server.Close()
+		provider, err := config.NewTokenProvider(config.Config{AuthEndpoint: endpoint.server.URL, AuthRealm: "senergy", AuthClientId: "marshaller"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := provider.Ensure()
+		if err != nil || token != "Bearer token-client_credentials" {
+			t.Error(token, err)
+		}
+		if len(endpoint.paths) != 1 || endpoint.paths[0] != "/auth/realms/senergy/protocol/openid-connect/token" {
+			t.Error(endpoint.paths)
+		}
+	})
+
+	t.Run("oidc token url", func(t *testing.T) {
+		endpoint := newTestTokenEndpoint(3600)
+		defer endpoint.server.Close()
+		provider, err := config.NewTokenProvider(config.Config{AuthTokenProvider: config.TokenProviderOidc, AuthEndpoint: "http://unused", AuthTokenUrl: endpoint.server.URL + "/oauth/token"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = provider.Ensure()
+		if err != nil {
+			t.Error(err)
+		}
+		if len(endpoint.paths) != 1 || endpoint.paths[0] != "/oauth/token" {
+			t.Error(endpoint.paths)
+		}
+	})
+
+	t.Run("oidc concurrent ensure", func(t *testing.T) {
+		endpoint := newTestTokenEndpoint(3600)
+		defer endpoint.server.Close()
+		provider, err := config.NewTokenProvider(config.Config{AuthTokenUrl: endpoint.server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg := sync.WaitGroup{}
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				token, err := provider.Ensure()
+				if err != nil || token != "Bearer token-client_credentials" {
+					t.Error(token, err)
+				}
+			}()
+		}
+		wg.Wait()
+		if endpoint.requestsTotal.Load() != 1 {
+			t.Error(endpoint.requestsTotal.Load())
+		}
+	})
+
+	t.Run("oidc refresh", func(t *testing.T) {
+		endpoint := newTestTokenEndpoint(0)
+		defer endpoint.server.Close()
+		provider, err := config.NewTokenProvider(config.Config{AuthTokenUrl: endpoint.server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg := sync.WaitGroup{}
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := provider.Ensure()
+				if err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		if endpoint.grants["client_credentials"] != 1 || endpoint.grants["refresh_token"] != 4 {
+			t.Error(endpoint.grants)
+		}
+	})
+
+	t.Run("static", func(t *testing.T) {
+		provider, err := config.NewTokenProvider(config.Config{AuthTokenProvider: config.TokenProviderStatic, AuthStaticToken: "foo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := provider.Ensure()
+		if err != nil || token != "Bearer foo" {
+			t.Error(token, err)
+		}
+		_, err = config.NewTokenProvider(config.Config{AuthTokenProvider: config.TokenProviderStatic})
+		if err == nil {
+			t.Error("expected error for missing static token")
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		location := filepath.Join(t.TempDir(), "token")
+		err := os.WriteFile(location, []byte("first\n"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		provider, err := config.NewTokenProvider(config.Config{AuthTokenProvider: config.TokenProviderFile, AuthTokenFile: location})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := provider.Ensure()
+		if err != nil || token != "Bearer first" {
+			t.Error(token, err)
+		}
+		err = os.WriteFile(location, []byte("Bearer rotated"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		later := time.Now().Add(time.Minute)
+		err = os.Chtimes(location, later, later)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err = provider.Ensure()
+		if err != nil || token != "Bearer rotated" {
+			t.Error(token, err)
+		}
+		err = os.Remove(location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = provider.Ensure()
+		if err == nil {
+			t.Error("expected error for missing token file")
+		}
+	})
+
+	t.Run("mtls", func(t *testing.T) {
+		provider, err := config.NewTokenProvider(config.Config{AuthTokenProvider: config.TokenProviderMTLS})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := provider.Ensure()
+		if err != nil || token != "" {
+			t.Error(token, err)
+		}
+		_, err = config.NewTokenProvider(config.Config{AuthTokenProvider: config.TokenProviderMTLS, AuthClientCertFile: "/unknown/cert.pem", AuthClientKeyFile: "/unknown/key.pem"})
+		if err == nil {
+			t.Error("expected error for missing client certificate")
+		}
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+		token.SetAuthorization(req)
+		if _, ok := req.Header["Authorization"]; ok {
+			t.Error("empty token should not be sent")
+		}
+	})
+
+	t.Run("mtls client certificate", func(t *testing.T) {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.TLS == nil || len(request.TLS.PeerCertificates) == 0 {
+				http.Error(writer, "missing client certificate", http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(writer).Encode(model.Protocol{Id: "p1"})
+		}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		server.StartTLS()
+		defer server.Close()
+
+		dir := t.TempDir()
+		certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+		writeClientCertificate(t, certFile, keyFile)
+		caFile := filepath.Join(dir, "ca.pem")
+		err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defaultTlsConfig := http.DefaultTransport.(*http.Transport).TLSClientConfig
+		conf := config.Config{AuthTokenProvider: config.TokenProviderMTLS, AuthClientCertFile: certFile, AuthClientKeyFile: keyFile, AuthCaFile: caFile, DeviceRepositoryUrl: server.URL}
+		provider, err := config.NewTokenProvider(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if http.DefaultTransport.(*http.Transport).TLSClientConfig != defaultTlsConfig {
+			t.Error("http.DefaultTransport should not be changed")
+		}
+		_, err = http.Get(server.URL)
+		if err == nil {
+			t.Error("expected unknown authority error for default client")
+		}
+
+		repo, err := devicerepository.New(conf, provider)
+		if err != nil {
+			t.Fatal(err)
+		}
+		protocol, err := repo.GetProtocol("p1")
+		if err != nil || protocol.Id != "p1" {
+			t.Error(protocol, err)
+		}
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := config.NewTokenProvider(config.Config{AuthTokenProvider: "foo"})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func writeClientCertificate(t *testing.T, certFile string, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "marshaller"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,333 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/converter/lib/converter/characteristics"
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
+)
+
+func TestMarshalConfigurables(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+		},
+	}
+	service := model.Service{
+		Id:          "sid",
+		LocalId:     "slid",
+		Name:        "sname",
+		Interaction: model.REQUEST,
+		ProtocolId:  "p1",
+		Inputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:   "payload",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:               "temperature",
+							Name:             "temperature",
+							Type:             model.Float,
+							CharacteristicId: characteristics.Celsius,
+							FunctionId:       model.CONTROLLING_FUNCTION_PREFIX + "setTemperature",
+							Value:            10,
+						},
+						{
+							Id:               "color",
+							Name:             "color",
+							Type:             model.Structure,
+							CharacteristicId: characteristics.Rgb,
+							SubContentVariables: []model.ContentVariable{
+								{Id: "r", Name: "r", Type: model.Integer, CharacteristicId: characteristics.RgbR, Value: 0},
+								{Id: "g", Name: "g", Type: model.Integer, CharacteristicId: characteristics.RgbG, Value: 0},
+								{Id: "b", Name: "b", Type: model.Integer, CharacteristicId: characteristics.RgbB, Value: 0},
+							},
+						},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	rgbConfigurable := configurables.Configurable{
+		CharacteristicId: characteristics.Rgb,
+		Values: []configurables.ConfigurableCharacteristicValue{
+			{Path: "r", Value: "255"},
+			{Path: "g", Value: "0"},
+			{Path: "b", Value: "100"},
+		},
+	}
+	temperatureConfigurable := configurables.Configurable{
+		CharacteristicId: characteristics.Celsius,
+		Values:           []configurables.ConfigurableCharacteristicValue{{Path: "", Value: "21"}},
+	}
+
+	t.Run("apply to matching concept", testMarshal(apiurl, messages.MarshallingV2Request{
+		Service:  service,
+		Protocol: protocol,
+		Data: []model.MarshallingV2RequestData{
+			{
+				Value:            30,
+				CharacteristicId: characteristics.Celsius,
+				FunctionId:       model.CONTROLLING_FUNCTION_PREFIX + "setTemperature",
+			},
+		},
+		Configurables: []configurables.Configurable{rgbConfigurable},
+	}, map[string]string{"body": `{"color":{"b":100,"g":0,"r":255},"temperature":30}`}))
+
+	t.Run("never override data", testMarshal(apiurl, messages.MarshallingV2Request{
+		Service:  service,
+		Protocol: protocol,
+		Data: []model.MarshallingV2RequestData{
+			{
+				Value:            30,
+				CharacteristicId: characteristics.Celsius,
+				Paths:            []string{"payload.temperature"},
+			},
+		},
+		Configurables: []configurables.Configurable{temperatureConfigurable, rgbConfigurable},
+	}, map[string]string{"body": `{"color":{"b":100,"g":0,"r":255},"temperature":30}`}))
+
+	t.Run("without data", testMarshal(apiurl, messages.MarshallingV2Request{
+		Service:       service,
+		Protocol:      protocol,
+		Configurables: []configurables.Configurable{temperatureConfigurable},
+	}, map[string]string{"body": `{"color":{"b":0,"g":0,"r":0},"temperature":21}`}))
+
+	t.Run("variable names with dots", testMarshal(apiurl, messages.MarshallingV2Request{
+		Service: model.Service{
+			Id:          "sid",
+			Interaction: model.REQUEST,
+			ProtocolId:  "p1",
+			Inputs: []model.Content{
+				{
+					Id: "content",
+					ContentVariable: model.ContentVariable{
+						Id:   "payload",
+						Name: "payload",
+						Type: model.Structure,
+						SubContentVariables: []model.ContentVariable{
+							{Id: "dotted", Name: "temp.inside", Type: model.Float, CharacteristicId: characteristics.Celsius, Value: 10},
+							{
+								Id:   "temp",
+								Name: "temp",
+								Type: model.Structure,
+								SubContentVariables: []model.ContentVariable{
+									{Id: "inside", Name: "inside", Type: model.Float, CharacteristicId: characteristics.Celsius, Value: 10},
+								},
+							},
+						},
+					},
+					Serialization:     "json",
+					ProtocolSegmentId: "p1.1",
+				},
+			},
+		},
+		Protocol: protocol,
+		Data: []model.MarshallingV2RequestData{
+			{
+				Value:            30,
+				CharacteristicId: characteristics.Celsius,
+				Paths:            []string{`payload.temp\.inside`},
+			},
+		},
+		Configurables: []configurables.Configurable{temperatureConfigurable},
+	}, map[string]string{"body": `{"temp":{"inside":21},"temp.inside":30}`}))
+
+	t.Run("lookup paths", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		err := json.NewEncoder(body).Encode(messages.FindConfigurablesRequest{
+			CharacteristicId: characteristics.Celsius,
+			Services:         []model.Service{service},
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Post(apiurl+"/configurables", "application/json", body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		result := []configurables.Configurable{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result) != 1 {
+			t.Error(result)
+			return
+		}
+		expected := []configurables.ConfigurablePath{{ServiceId: "sid", Path: "payload.color", CharacteristicId: characteristics.Rgb}}
+		if !reflect.DeepEqual(result[0].Paths, expected) {
+			t.Error(result[0].Paths)
+		}
+	})
+}
+
+func TestFindFunctionConfigurables(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	setTemperature := model.CONTROLLING_FUNCTION_PREFIX + "setTemperature"
+	setColor := model.CONTROLLING_FUNCTION_PREFIX + "setColor"
+
+	mocks.DeviceRepo.SetDeviceType(model.DeviceType{
+		Id: "dt1",
+		Services: []model.Service{
+			{
+				Id:         "s1",
+				ProtocolId: "p1",
+				Inputs: []model.Content{
+					{
+						Id: "content",
+						ContentVariable: model.ContentVariable{
+							Id:   "payload",
+							Name: "payload",
+							Type: model.Structure,
+							SubContentVariables: []model.ContentVariable{
+								{
+									Id:               "temperature",
+									Name:             "temperature",
+									Type:             model.Float,
+									CharacteristicId: characteristics.Celsius,
+									FunctionId:       setTemperature,
+									AspectId:         "air",
+								},
+								{
+									Id:               "color",
+									Name:             "color",
+									Type:             model.Structure,
+									CharacteristicId: characteristics.Rgb,
+									FunctionId:       setColor,
+									AspectId:         "device",
+									SubContentVariables: []model.ContentVariable{
+										{Id: "r", Name: "r", Type: model.Integer, CharacteristicId: characteristics.RgbR},
+										{Id: "g", Name: "g", Type: model.Integer, CharacteristicId: characteristics.RgbG},
+										{Id: "b", Name: "b", Type: model.Integer, CharacteristicId: characteristics.RgbB},
+									},
+								},
+								{
+									Id:   "unrelated",
+									Name: "unrelated",
+									Type: model.String,
+								},
+							},
+						},
+						Serialization:     "json",
+						ProtocolSegmentId: "p1.1",
+					},
+				},
+			},
+			{
+				Id:         "s2",
+				ProtocolId: "p1",
+				Inputs: []model.Content{
+					{
+						Id: "content",
+						ContentVariable: model.ContentVariable{
+							Id:               "color",
+							Name:             "color",
+							Type:             model.String,
+							CharacteristicId: characteristics.Hex,
+							FunctionId:       setColor,
+						},
+						Serialization:     "json",
+						ProtocolSegmentId: "p1.1",
+					},
+				},
+			},
+		},
+	}).SetDeviceType(model.DeviceType{Id: "dt2"})
+
+	result := []configurables.DeviceTypeConfigurables{}
+	resp, err := http.Get(apiurl + "/v2/configurables?function_id=" + setTemperature + "&device_type_ids=dt1,dt2")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Error(resp.StatusCode)
+		return
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []configurables.DeviceTypeConfigurables{
+		{
+			DeviceTypeId: "dt1",
+			Services: []configurables.ServiceConfigurables{
+				{
+					ServiceId:     "s1",
+					FunctionPaths: []string{"payload.temperature"},
+					Configurables: []configurables.FunctionConfigurable{
+						{
+							Path:             "payload.color",
+							FunctionId:       setColor,
+							AspectId:         "device",
+							ConceptId:        "urn:infai:ses:concept:8b1161d5-7878-4dd2-a36c-6f98f6b94bf8",
+							CharacteristicId: characteristics.Rgb,
+							Values: []configurables.ConfigurableCharacteristicValue{
+								{Label: "RGB b", Path: "b", Value: "0", CharacteristicId: characteristics.RgbB, Type: model.Integer},
+								{Label: "RGB g", Path: "g", Value: "0", CharacteristicId: characteristics.RgbG, Type: model.Integer},
+								{Label: "RGB r", Path: "r", Value: "0", CharacteristicId: characteristics.RgbR, Type: model.Integer},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		resultJson, _ := json.Marshal(result)
+		t.Error(string(resultJson))
+	}
+}
@@ -19,8 +19,10 @@ package v2
 import (
 	"context"
 	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
 	"github.com/SENERGY-Platform/marshaller/lib/config"
 	"github.com/SENERGY-Platform/marshaller/lib/configurables"
+	"github.com/SENERGY-Platform/marshaller/lib/health"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller"
 	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
 	"github.com/SENERGY-Platform/marshaller/lib/tests/mocks"
@@ -29,15 +31,19 @@ import (
 )
 
 func setup(ctx context.Context, done *sync.WaitGroup) (serverUrl string) {
+	return setupWithConfig(ctx, done, config.Config{ReturnUnknownPathAsNull: true, Debug: true}, nil)
+}
+
+func setupWithConfig(ctx context.Context, done *sync.WaitGroup, conf config.Config, m *metrics.Metrics) (serverUrl string) {
 	conceptRepo, err := mocks.NewMockConceptRepo(ctx)
 	if err != nil {
 		panic(err)
 	}
 	marshaller := marshaller.New(mocks.Converter{}, conceptRepo, mocks.DeviceRepo)
-	marshallerv2 := v2.New(config.Config{ReturnUnknownPathAsNull: true, Debug: true}, mocks.Converter{}, conceptRepo)
-	configurableService := configurables.New(conceptRepo)
+	marshallerv2 := v2.New(conf, mocks.Converter{}, conceptRepo)
+	configurableService := configurables.New(conceptRepo, marshallerv2)
 	done.Add(1)
-	server := httptest.NewServer(api.GetRouter(config.Config{Debug: true}, marshaller, marshallerv2, configurableService, mocks.DeviceRepo, nil, nil))
+	server := httptest.NewServer(api.GetRouter(config.Config{Debug: true}, marshaller, marshallerv2, configurableService, mocks.DeviceRepo, nil, m, health.New(config.Config{}, conceptRepo), conceptRepo))
 	serverUrl = server.URL
 	go func() {
 		<-ctx.Done()
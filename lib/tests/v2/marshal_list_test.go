@@ -21,8 +21,13 @@ import (
 	"github.com/SENERGY-Platform/converter/lib/converter/characteristics"
 	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
 	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
+
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
 )
 
 func TestMarshalListIndexed(t *testing.T) {
@@ -395,3 +400,163 @@ func TestMarshalListCharacteristic(t *testing.T) {
 	}, map[string]string{"body": `{"customOrder":true,"iterations":1,"segment_ids":["1","2"]}`}))
 
 }
+
+func TestMarshalListElements(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+		},
+	}
+	service := model.Service{
+		Id:          "sid",
+		LocalId:     "slid",
+		Name:        "sname",
+		Interaction: model.REQUEST,
+		ProtocolId:  "p1",
+		Inputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:   "payload",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:                   "lights",
+							Name:                 "lights",
+							Type:                 model.List,
+							SerializationOptions: []string{model.SerializationOptionMaxLengthPrefix + "4"},
+							SubContentVariables: []model.ContentVariable{
+								{
+									Id:   "light",
+									Name: "*",
+									Type: model.Structure,
+									SubContentVariables: []model.ContentVariable{
+										{Id: "on", Name: "on", Type: model.Boolean, Value: false},
+										{Id: "brightness", Name: "brightness", Type: model.Integer, Value: 100},
+									},
+								},
+							},
+						},
+						{
+							Id:   "temperatures",
+							Name: "temperatures",
+							Type: model.List,
+							SubContentVariables: []model.ContentVariable{
+								{Id: "temperature", Name: "*", Type: model.Integer, CharacteristicId: characteristics.Celsius, Value: 0},
+							},
+						},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	request := func(data ...model.MarshallingV2RequestData) messages.MarshallingV2Request {
+		return messages.MarshallingV2Request{Service: service, Protocol: protocol, Data: data}
+	}
+
+	t.Run("by index", testMarshal(apiurl, request(
+		model.MarshallingV2RequestData{Value: 50, Paths: []string{"payload.lights.2.brightness"}},
+	), map[string]string{"body": `{"lights":[{"brightness":100,"on":false},{"brightness":100,"on":false},{"brightness":50,"on":false}],"temperatures":[0]}`}))
+
+	t.Run("append", testMarshal(apiurl, request(
+		model.MarshallingV2RequestData{Value: 10, Paths: []string{"payload.lights.+.brightness"}},
+		model.MarshallingV2RequestData{Value: true, Paths: []string{"payload.lights.+.on"}},
+	), map[string]string{"body": `{"lights":[{"brightness":10,"on":false},{"brightness":100,"on":true}],"temperatures":[0]}`}))
+
+	t.Run("index and append", testMarshal(apiurl, request(
+		model.MarshallingV2RequestData{Value: true, Paths: []string{"payload.lights.1.on"}},
+		model.MarshallingV2RequestData{Value: 5, Paths: []string{"payload.lights.+.brightness"}},
+		model.MarshallingV2RequestData{Value: 70, Paths: []string{"payload.lights.0.brightness"}},
+	), map[string]string{"body": `{"lights":[{"brightness":70,"on":false},{"brightness":100,"on":true},{"brightness":5,"on":false}],"temperatures":[0]}`}))
+
+	t.Run("element conversion", testMarshal(apiurl, request(
+		model.MarshallingV2RequestData{Value: 300, CharacteristicId: characteristics.Kelvin, Paths: []string{"payload.temperatures.1"}},
+	), map[string]string{"body": `{"lights":[{"brightness":100,"on":false}],"temperatures":[0,27]}`}))
+
+	t.Run("max length", testMarshalViolations(apiurl, request(
+		model.MarshallingV2RequestData{Value: 50, Paths: []string{"payload.lights.4.brightness"}},
+	), []validation.Violation{{Path: "payload.lights", Reason: validation.ReasonMaxLength}}))
+
+	t.Run("huge index with max length", testMarshalViolations(apiurl, request(
+		model.MarshallingV2RequestData{Value: 50, Paths: []string{"payload.lights.999999999.brightness"}},
+	), []validation.Violation{{Path: "payload.lights", Reason: validation.ReasonMaxLength}}))
+
+	t.Run("huge index without max length", testMarshalViolations(apiurl, request(
+		model.MarshallingV2RequestData{Value: 50, Paths: []string{"payload.temperatures.999999999"}},
+	), []validation.Violation{{Path: "payload.temperatures", Reason: validation.ReasonMaxLength}}))
+
+	t.Run("max index without max length", testMarshal(apiurl, request(
+		model.MarshallingV2RequestData{Value: 50, Paths: []string{"payload.temperatures." + strconv.Itoa(v2.MaxVariableLenListLength-1)}},
+	), map[string]string{"body": `{"lights":[{"brightness":100,"on":false}],"temperatures":[` + strings.Repeat("0,", v2.MaxVariableLenListLength-1) + `50]}`}))
+
+	fixedService := service
+	fixedService.Inputs = []model.Content{
+		{
+			Id: "content",
+			ContentVariable: model.ContentVariable{
+				Id:   "payload",
+				Name: "payload",
+				Type: model.Structure,
+				SubContentVariables: []model.ContentVariable{
+					{
+						Id:   "pair",
+						Name: "pair",
+						Type: model.List,
+						SubContentVariables: []model.ContentVariable{
+							{Id: "first", Name: "0", Type: model.Integer, Value: 1},
+							{Id: "second", Name: "1", Type: model.Integer, Value: 2},
+						},
+					},
+					{
+						Id:   "mixed",
+						Name: "mixed",
+						Type: model.List,
+						SubContentVariables: []model.ContentVariable{
+							{Id: "element", Name: "*", Type: model.Integer, Value: 0},
+							{Id: "extra", Name: "extra", Type: model.Integer, Value: 0},
+						},
+					},
+					{Id: "name", Name: "name", Type: model.String, Value: "foo"},
+				},
+			},
+			Serialization:     "json",
+			ProtocolSegmentId: "p1.1",
+		},
+	}
+	fixedRequest := func(data ...model.MarshallingV2RequestData) messages.MarshallingV2Request {
+		return messages.MarshallingV2Request{Service: fixedService, Protocol: protocol, Data: data}
+	}
+
+	t.Run("index beyond fixed list", testMarshalViolations(apiurl, fixedRequest(
+		model.MarshallingV2RequestData{Value: 5, Paths: []string{"payload.pair.2"}},
+	), []validation.Violation{{Path: "payload.pair", Reason: validation.ReasonMaxLength}}))
+
+	t.Run("append to fixed list", testMarshalViolations(apiurl, fixedRequest(
+		model.MarshallingV2RequestData{Value: 5, Paths: []string{"payload.pair.+"}},
+	), []validation.Violation{{Path: "payload.pair", Reason: validation.ReasonType}}))
+
+	t.Run("append to list with additional sub variables", testMarshalViolations(apiurl, fixedRequest(
+		model.MarshallingV2RequestData{Value: 5, Paths: []string{"payload.mixed.+"}},
+	), []validation.Violation{{Path: "payload.mixed", Reason: validation.ReasonType}}))
+
+	t.Run("append to non list", testMarshalViolations(apiurl, fixedRequest(
+		model.MarshallingV2RequestData{Value: "bar", Paths: []string{"payload.name.+"}},
+	), []validation.Violation{{Path: "payload.name", Reason: validation.ReasonType}}))
+
+	t.Run("append to structure", testMarshalViolations(apiurl, fixedRequest(
+		model.MarshallingV2RequestData{Value: "bar", Paths: []string{"payload.+"}},
+	), []validation.Violation{{Path: "payload", Reason: validation.ReasonType}}))
+}
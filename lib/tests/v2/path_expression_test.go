@@ -0,0 +1,204 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/pathexpr"
+)
+
+func TestParsePathExpression(t *testing.T) {
+	valid := map[string]int{
+		"a.b.c":                         3,
+		`a\.b.c`:                        2,
+		"a['b.c']":                      2,
+		"a[*].b":                        3,
+		"a.-1":                          2,
+		"a[-1][0]":                      3,
+		"a[?(@.type=='temp')].value":    3,
+		`a[?(@.name == "x]y")]`:         2,
+		"a[?(@.value>=2.5)]":            2,
+		"a[?(@.enabled)]":               2,
+		"a[?(@.enabled == true)].0.foo": 4,
+	}
+	for path, segments := range valid {
+		expression, err := pathexpr.Parse(path)
+		if err != nil {
+			t.Error(path, err)
+			continue
+		}
+		if len(expression) != segments {
+			t.Error(path, expression)
+		}
+	}
+	for _, path := range []string{"a..b", "a.", ".a", "a[", "a]", `a\`, "a[foo]", "a[?(type=='x')]", "a[?(@[*].b)]"} {
+		_, err := pathexpr.Parse(path)
+		if err == nil {
+			t.Error("expected error for", path)
+		}
+	}
+}
+
+func TestPathExpressions(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+		},
+	}
+
+	sensor := func(name string, sensorType string) model.ContentVariable {
+		return model.ContentVariable{
+			Id:   name,
+			Name: name,
+			Type: model.Structure,
+			SubContentVariables: []model.ContentVariable{
+				{Id: name + ".type", Name: "type", Type: model.String, Value: sensorType},
+				{Id: name + ".value", Name: "value", Type: model.Integer, Value: 0},
+			},
+		}
+	}
+
+	service := model.Service{
+		Id:          "sid",
+		LocalId:     "slid",
+		Name:        "sname",
+		Interaction: model.EVENT_AND_REQUEST,
+		ProtocolId:  "p1",
+		Inputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:   "payload",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:                  "sensors",
+							Name:                "sensors",
+							Type:                model.List,
+							SubContentVariables: []model.ContentVariable{sensor("0", "temp"), sensor("1", "hum"), sensor("2", "temp")},
+						},
+						{Id: "dotted", Name: "a.b", Type: model.Integer, Value: 0},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+		Outputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:   "payload",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:   "sensors",
+							Name: "sensors",
+							Type: model.List,
+							SubContentVariables: []model.ContentVariable{{
+								Id:   "sensor",
+								Name: "*",
+								Type: model.Structure,
+								SubContentVariables: []model.ContentVariable{
+									{Id: "type", Name: "type", Type: model.String},
+									{Id: "value", Name: "value", Type: model.Integer},
+								},
+							}},
+						},
+						{Id: "dotted", Name: "a.b", Type: model.Integer},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	output := map[string]string{"body": `{"sensors":[{"type":"temp","value":21},{"type":"hum","value":40},{"type":"temp","value":23}],"a.b":5}`}
+	unmarshalRequest := func(path string) messages.UnmarshallingV2Request {
+		return messages.UnmarshallingV2Request{
+			Service:  service,
+			Protocol: protocol,
+			Message:  output,
+			Path:     path,
+		}
+	}
+
+	t.Run("unmarshal wildcard", testUnmarshal(apiurl, unmarshalRequest("payload.sensors[*].value"), []interface{}{21.0, 40.0, 23.0}))
+	t.Run("unmarshal negative index", testUnmarshal(apiurl, unmarshalRequest("payload.sensors[-1].value"), 23.0))
+	t.Run("unmarshal negative index segment", testUnmarshal(apiurl, unmarshalRequest("payload.sensors.-2.type"), "hum"))
+	t.Run("unmarshal filter", testUnmarshal(apiurl, unmarshalRequest("payload.sensors[?(@.type=='temp')].value"), []interface{}{21.0, 23.0}))
+	t.Run("unmarshal number filter", testUnmarshal(apiurl, unmarshalRequest("payload.sensors[?(@.value > 30)].type"), []interface{}{"hum"}))
+	t.Run("unmarshal filter without match", testUnmarshal(apiurl, unmarshalRequest("payload.sensors[?(@.type=='foo')].value"), []interface{}{}))
+	t.Run("unmarshal escaped dot", testUnmarshal(apiurl, unmarshalRequest(`payload.a\.b`), 5.0))
+	t.Run("unmarshal quoted name", testUnmarshal(apiurl, unmarshalRequest(`payload['a.b']`), 5.0))
+	t.Run("unmarshal legacy placeholder", testUnmarshal(apiurl, unmarshalRequest("payload.sensors.*.value"), 21.0))
+
+	t.Run("unmarshal invalid expression", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		err := json.NewEncoder(body).Encode(unmarshalRequest("payload.sensors[?(@.type=='temp').value"))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Post(apiurl+"/v2/unmarshal", "application/json", body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			t.Error(resp.StatusCode)
+		}
+	})
+
+	marshalRequest := func(path string, value interface{}) messages.MarshallingV2Request {
+		return messages.MarshallingV2Request{
+			Service:  service,
+			Protocol: protocol,
+			Data:     []model.MarshallingV2RequestData{{Value: value, Paths: []string{path}}},
+		}
+	}
+
+	t.Run("marshal filter", testMarshal(apiurl, marshalRequest("payload.sensors[?(@.type=='temp')].value", 20),
+		map[string]string{"body": `{"a.b":0,"sensors":[{"type":"temp","value":20},{"type":"hum","value":0},{"type":"temp","value":20}]}`}))
+	t.Run("marshal wildcard", testMarshal(apiurl, marshalRequest("payload.sensors[*].value", 1),
+		map[string]string{"body": `{"a.b":0,"sensors":[{"type":"temp","value":1},{"type":"hum","value":1},{"type":"temp","value":1}]}`}))
+	t.Run("marshal negative index", testMarshal(apiurl, marshalRequest("payload.sensors[-2].value", 3),
+		map[string]string{"body": `{"a.b":0,"sensors":[{"type":"temp","value":0},{"type":"hum","value":3},{"type":"temp","value":0}]}`}))
+	t.Run("marshal escaped dot", testMarshal(apiurl, marshalRequest(`payload.a\.b`, 7),
+		map[string]string{"body": `{"a.b":7,"sensors":[{"type":"temp","value":0},{"type":"hum","value":0},{"type":"temp","value":0}]}`}))
+}
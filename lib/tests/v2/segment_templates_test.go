@@ -0,0 +1,138 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	v2 "github.com/SENERGY-Platform/marshaller/lib/marshaller/v2"
+	"github.com/SENERGY-Platform/models/go/models"
+)
+
+func TestSegmentTemplates(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+			{Id: "p1.2", Name: "topic"},
+			{Id: "p1.3", Name: "header"},
+			{Id: "p1.4", Name: "auth"},
+		},
+	}
+	service := model.Service{
+		Id:          "sid",
+		LocalId:     "slid",
+		Name:        "sname",
+		Interaction: model.REQUEST,
+		ProtocolId:  "p1",
+		Attributes: []models.Attribute{
+			{Key: v2.SegmentTemplateAttributePrefix + "topic", Value: "cmd/{{.DeviceLocalId}}/{{.ServiceLocalId}}"},
+			{Key: v2.SegmentTemplateAttributePrefix + "header", Value: `{"service":"{{.ServiceId}}","brightness":{{index .Values "payload.brightness"}}}`},
+			{Key: "other", Value: "ignored"},
+		},
+		Inputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:   "payload",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{Id: "brightness", Name: "brightness", Type: model.Integer, Value: 0},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	request := messages.MarshallingV2Request{
+		Service:       service,
+		Protocol:      protocol,
+		Data:          []model.MarshallingV2RequestData{{Value: 42, Paths: []string{"payload.brightness"}}},
+		DeviceId:      "did",
+		DeviceLocalId: "dlid",
+	}
+
+	t.Run("service attributes", testMarshal(apiurl, request, map[string]string{
+		"body":   `{"brightness":42}`,
+		"topic":  "cmd/dlid/slid",
+		"header": `{"service":"sid","brightness":42}`,
+	}))
+
+	//templates are read from the service only, so other services of the same protocol are not affected
+	withoutAttributes := request
+	withoutAttributes.Service.Attributes = nil
+	t.Run("service without attributes", testMarshal(apiurl, withoutAttributes, map[string]string{
+		"body": `{"brightness":42}`,
+	}))
+
+	withRequestTemplates := request
+	withRequestTemplates.SegmentTemplates = map[string]string{
+		"auth":  "static-secret",
+		"topic": "{{.DeviceId}}/set",
+		"body":  `{"wrapped":{{.Segments.body}}}`,
+	}
+	t.Run("request templates", testMarshal(apiurl, withRequestTemplates, map[string]string{
+		"body":   `{"wrapped":{"brightness":42}}`,
+		"topic":  "did/set",
+		"header": `{"service":"sid","brightness":42}`,
+		"auth":   "static-secret",
+	}))
+
+	for name, templates := range map[string]map[string]string{
+		"unknown segment": {"foo": "bar"},
+		"invalid syntax":  {"auth": "{{.DeviceId"},
+		"unknown field":   {"auth": "{{.Foo}}"},
+	} {
+		invalid := request
+		invalid.SegmentTemplates = templates
+		t.Run(name, func(t *testing.T) {
+			body := new(bytes.Buffer)
+			err := json.NewEncoder(body).Encode(invalid)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := http.Post(apiurl+"/v2/marshal", "application/json", body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				t.Error(resp.StatusCode)
+			}
+		})
+	}
+}
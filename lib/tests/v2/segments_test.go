@@ -0,0 +1,160 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/models/go/models"
+)
+
+func TestSharedProtocolSegments(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+			{Id: "p1.2", Name: "header"},
+		},
+	}
+	structure := func(name string, serialization models.Serialization, segment string, fields ...model.ContentVariable) model.Content {
+		return model.Content{
+			Id:                name,
+			ContentVariable:   model.ContentVariable{Id: name, Name: name, Type: model.Structure, SubContentVariables: fields},
+			Serialization:     serialization,
+			ProtocolSegmentId: segment,
+		}
+	}
+	text := func(name string, segment string, options ...string) model.Content {
+		return model.Content{
+			Id:                name,
+			ContentVariable:   model.ContentVariable{Id: name, Name: name, Type: model.String, SerializationOptions: options, Value: ""},
+			Serialization:     models.PlainText,
+			ProtocolSegmentId: segment,
+		}
+	}
+	field := func(name string, t model.Type, value interface{}) model.ContentVariable {
+		return model.ContentVariable{Id: name, Name: name, Type: t, Value: value}
+	}
+	service := func(inputs ...model.Content) model.Service {
+		return model.Service{
+			Id:          "sid",
+			LocalId:     "slid",
+			Name:        "sname",
+			Interaction: model.EVENT_AND_REQUEST,
+			ProtocolId:  "p1",
+			Inputs:      inputs,
+			Outputs:     inputs,
+		}
+	}
+	marshalRequest := func(service model.Service, data ...model.MarshallingV2RequestData) messages.MarshallingV2Request {
+		return messages.MarshallingV2Request{Service: service, Protocol: protocol, Data: data}
+	}
+	unmarshalRequest := func(service model.Service, path string, message map[string]string) messages.UnmarshallingV2Request {
+		return messages.UnmarshallingV2Request{Service: service, Protocol: protocol, Path: path, Message: message}
+	}
+
+	jsonService := service(
+		structure("state", models.JSON, "p1.1", field("on", model.Boolean, false)),
+		structure("level", models.JSON, "p1.1", field("brightness", model.Integer, 0)),
+		structure("meta", models.JSON, "p1.2", field("id", model.String, "foo")),
+	)
+	t.Run("marshal json merge", testMarshal(apiurl, marshalRequest(jsonService,
+		model.MarshallingV2RequestData{Value: true, Paths: []string{"state.on"}},
+		model.MarshallingV2RequestData{Value: 42, Paths: []string{"level.brightness"}},
+	), map[string]string{"body": `{"brightness":42,"on":true}`, "header": `{"id":"foo"}`}))
+
+	t.Run("unmarshal json split", testUnmarshal(apiurl, unmarshalRequest(jsonService, "level", map[string]string{"body": `{"brightness":42,"on":true}`}),
+		map[string]interface{}{"brightness": 42.0}))
+
+	conflictService := service(
+		structure("a", models.JSON, "p1.1", field("on", model.Boolean, false)),
+		structure("b", models.JSON, "p1.1", field("on", model.Boolean, true)),
+	)
+	t.Run("marshal json conflict", testMarshalError(apiurl, marshalRequest(conflictService)))
+
+	xmlService := service(
+		structure("root", models.XML, "p1.1", field("on", model.Boolean, false)),
+		structure("root", models.XML, "p1.1", field("brightness", model.Integer, 0)),
+	)
+	t.Run("marshal xml merge", testMarshal(apiurl, marshalRequest(xmlService,
+		model.MarshallingV2RequestData{Value: 42, Paths: []string{"root.brightness"}},
+	), map[string]string{"body": `<root><brightness>42</brightness><on>false</on></root>`}))
+
+	t.Run("unmarshal xml merge", testUnmarshal(apiurl, unmarshalRequest(xmlService, "root", map[string]string{"body": `<root><brightness>42</brightness><on>true</on></root>`}),
+		map[string]interface{}{"brightness": 42.0, "on": true}))
+
+	xmlRootService := service(
+		structure("a", models.XML, "p1.1", field("on", model.Boolean, false)),
+		structure("b", models.XML, "p1.1", field("brightness", model.Integer, 0)),
+	)
+	t.Run("marshal xml different roots", testMarshalError(apiurl, marshalRequest(xmlRootService)))
+
+	textService := service(
+		text("device", "p1.1", model.SerializationOptionSeparatorPrefix+"/"),
+		text("command", "p1.1", model.SerializationOptionSeparatorPrefix+"/"),
+	)
+	t.Run("marshal text concatenation", testMarshal(apiurl, marshalRequest(textService,
+		model.MarshallingV2RequestData{Value: "lamp", Paths: []string{"device"}},
+		model.MarshallingV2RequestData{Value: "on", Paths: []string{"command"}},
+	), map[string]string{"body": `lamp/on`}))
+
+	t.Run("unmarshal text split", testUnmarshal(apiurl, unmarshalRequest(textService, "command", map[string]string{"body": `lamp/off/now`}), "off/now"))
+
+	t.Run("marshal text without separator", testMarshalError(apiurl, marshalRequest(service(text("device", "p1.1"), text("command", "p1.1")))))
+
+	t.Run("marshal mixed serializations", testMarshalError(apiurl, marshalRequest(service(
+		structure("state", models.JSON, "p1.1", field("on", model.Boolean, false)),
+		text("command", "p1.1", model.SerializationOptionSeparatorPrefix+"/"),
+	))))
+}
+
+func testMarshalError(apiurl string, request messages.MarshallingV2Request) func(t *testing.T) {
+	return func(t *testing.T) {
+		body := new(bytes.Buffer)
+		err := json.NewEncoder(body).Encode(request)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Post(apiurl+"/v2/marshal", "application/json", body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 300 {
+			buf := new(bytes.Buffer)
+			buf.ReadFrom(resp.Body)
+			t.Error(resp.StatusCode, buf.String())
+		}
+	}
+}
@@ -344,3 +344,116 @@ func TestUnarshalListVariable(t *testing.T) {
 		AspectNodeId:     "inside_air",
 	}, []interface{}{400.0, 500.0}))
 }
+
+func TestUnmarshalWholeList(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+		},
+	}
+	service := model.Service{
+		Id:          "sid",
+		LocalId:     "slid",
+		Name:        "sname",
+		Interaction: model.EVENT_AND_REQUEST,
+		ProtocolId:  "p1",
+		Outputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:   "payload",
+					Name: "payload",
+					Type: model.Structure,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:   "rooms",
+							Name: "rooms",
+							Type: model.List,
+							SubContentVariables: []model.ContentVariable{
+								{
+									Id:   "room",
+									Name: "*",
+									Type: model.Structure,
+									SubContentVariables: []model.ContentVariable{
+										{Id: "name", Name: "name", Type: model.String},
+										{
+											Id:   "sensors",
+											Name: "sensors",
+											Type: model.List,
+											SubContentVariables: []model.ContentVariable{
+												{
+													Id:               "temperature",
+													Name:             "*",
+													Type:             model.Float,
+													CharacteristicId: characteristics.Celsius,
+													FunctionId:       model.MEASURING_FUNCTION_PREFIX + "getTemperature",
+													AspectId:         "inside_air",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	output := map[string]string{"body": `{"rooms":[{"name":"kitchen","sensors":[20,21]},{"name":"bath","sensors":[25]}]}`}
+
+	t.Run("nested lists", testUnmarshal(apiurl, messages.UnmarshallingV2Request{
+		Service:          service,
+		Protocol:         protocol,
+		CharacteristicId: characteristics.Kelvin,
+		Message:          output,
+		Path:             "payload.rooms.*.sensors.*",
+		WholeList:        true,
+	}, []interface{}{
+		map[string]interface{}{"path": "payload.rooms.0.sensors.0", "value": 293.15, "characteristic_id": characteristics.Celsius, "function_id": model.MEASURING_FUNCTION_PREFIX + "getTemperature", "aspect_id": "inside_air"},
+		map[string]interface{}{"path": "payload.rooms.0.sensors.1", "value": 294.15, "characteristic_id": characteristics.Celsius, "function_id": model.MEASURING_FUNCTION_PREFIX + "getTemperature", "aspect_id": "inside_air"},
+		map[string]interface{}{"path": "payload.rooms.1.sensors.0", "value": 298.15, "characteristic_id": characteristics.Celsius, "function_id": model.MEASURING_FUNCTION_PREFIX + "getTemperature", "aspect_id": "inside_air"},
+	}))
+
+	t.Run("single list", testUnmarshal(apiurl, messages.UnmarshallingV2Request{
+		Service:   service,
+		Protocol:  protocol,
+		Message:   output,
+		Path:      "payload.rooms.*.name",
+		WholeList: true,
+	}, []interface{}{
+		map[string]interface{}{"path": "payload.rooms.0.name", "value": "kitchen"},
+		map[string]interface{}{"path": "payload.rooms.1.name", "value": "bath"},
+	}))
+
+	t.Run("by function", testUnmarshal(apiurl, messages.UnmarshallingV2Request{
+		Service:          service,
+		Protocol:         protocol,
+		CharacteristicId: characteristics.Celsius,
+		Message:          map[string]string{"body": `{"rooms":[{"name":"kitchen","sensors":[20]}]}`},
+		FunctionId:       model.MEASURING_FUNCTION_PREFIX + "getTemperature",
+		WholeList:        true,
+	}, []interface{}{
+		map[string]interface{}{"path": "payload.rooms.0.sensors.0", "value": 20.0, "characteristic_id": characteristics.Celsius, "function_id": model.MEASURING_FUNCTION_PREFIX + "getTemperature", "aspect_id": "inside_air"},
+	}))
+
+	t.Run("empty list", testUnmarshal(apiurl, messages.UnmarshallingV2Request{
+		Service:   service,
+		Protocol:  protocol,
+		Message:   map[string]string{"body": `{"rooms":[]}`},
+		Path:      "payload.rooms.*.sensors.*",
+		WholeList: true,
+	}, []interface{}{}))
+}
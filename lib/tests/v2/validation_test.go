@@ -0,0 +1,350 @@
+/*
+ * Copyright 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/marshaller/lib/api"
+	"github.com/SENERGY-Platform/marshaller/lib/api/messages"
+	"github.com/SENERGY-Platform/marshaller/lib/api/metrics"
+	"github.com/SENERGY-Platform/marshaller/lib/config"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/model"
+	"github.com/SENERGY-Platform/marshaller/lib/marshaller/validation"
+)
+
+func TestMarshalValidation(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	apiurl := setup(ctx, wg)
+
+	segmentCleaning := "urn:infai:ses:characteristic:f48d7985-7ee7-4119-a791-bc16a953f440"
+	iterations := "urn:infai:ses:characteristic:63769613-47bd-4bb9-9624-083669ee6261"
+	customOrder := "urn:infai:ses:characteristic:aeacd820-8a9e-4a68-a4c6-412537bb8afb"
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+		},
+	}
+	service := model.Service{
+		Id:          "sid",
+		LocalId:     "slid",
+		Name:        "sname",
+		Interaction: model.REQUEST,
+		ProtocolId:  "p1",
+		Inputs: []model.Content{
+			{
+				Id: "content",
+				ContentVariable: model.ContentVariable{
+					Id:               "root",
+					Name:             "root",
+					Type:             model.Structure,
+					CharacteristicId: segmentCleaning,
+					SubContentVariables: []model.ContentVariable{
+						{
+							Id:               "segment_ids",
+							Name:             "segment_ids",
+							Type:             model.List,
+							CharacteristicId: "urn:infai:ses:characteristic:b0bf0d79-8a23-40d3-a284-2b87e38138be",
+							SubContentVariables: []model.ContentVariable{
+								{
+									Id:               "var",
+									Name:             "*",
+									Type:             model.String,
+									CharacteristicId: "urn:infai:ses:characteristic:802c79a9-c96b-4848-848c-bae29fb00375",
+								},
+							},
+						},
+						{
+							Id:               "iterations",
+							Name:             "iterations",
+							Type:             model.Integer,
+							CharacteristicId: iterations,
+						},
+						{
+							Id:               "customOrder",
+							Name:             "customOrder",
+							Type:             model.Boolean,
+							CharacteristicId: customOrder,
+						},
+					},
+				},
+				Serialization:     "json",
+				ProtocolSegmentId: "p1.1",
+			},
+		},
+	}
+
+	validate := true
+	request := func(value interface{}, validate *bool) messages.MarshallingV2Request {
+		return messages.MarshallingV2Request{
+			Service:  service,
+			Protocol: protocol,
+			Data: []model.MarshallingV2RequestData{
+				{
+					Value:            value,
+					CharacteristicId: segmentCleaning,
+					Paths:            []string{"root"},
+				},
+			},
+			Validate: validate,
+		}
+	}
+
+	t.Run("valid", testMarshal(apiurl, request(map[string]interface{}{"iterations": 2, "customOrder": true, "segment_ids": []string{"1"}}, &validate),
+		map[string]string{"body": `{"customOrder":true,"iterations":2,"segment_ids":["1"]}`}))
+
+	t.Run("not validated by default", testMarshal(apiurl, request(map[string]interface{}{"iterations": 7, "customOrder": true, "segment_ids": []string{}}, nil),
+		map[string]string{"body": `{"customOrder":true,"iterations":7,"segment_ids":[]}`}))
+
+	t.Run("out of range", testMarshalViolations(apiurl, request(map[string]interface{}{"iterations": 7, "customOrder": true, "segment_ids": []string{}}, &validate), []validation.Violation{
+		{Path: "data.0.iterations", CharacteristicId: iterations, Reason: validation.ReasonMax},
+	}))
+
+	t.Run("wrong types", testMarshalViolations(apiurl, request(map[string]interface{}{"iterations": 0, "customOrder": "yes", "segment_ids": []interface{}{"1", 2}}, &validate), []validation.Violation{
+		{Path: "data.0.customOrder", CharacteristicId: customOrder, Reason: validation.ReasonType},
+		{Path: "data.0.iterations", CharacteristicId: iterations, Reason: validation.ReasonMin},
+		{Path: "data.0.segment_ids.1", CharacteristicId: "urn:infai:ses:characteristic:802c79a9-c96b-4848-848c-bae29fb00375", Reason: validation.ReasonType},
+	}))
+
+	t.Run("missing field", testMarshalViolations(apiurl, request(map[string]interface{}{"iterations": 2, "segment_ids": []string{}}, &validate), []validation.Violation{
+		{Path: "data.0.customOrder", CharacteristicId: customOrder, Reason: validation.ReasonRequired},
+	}))
+
+	t.Run("allowed values", func(t *testing.T) {
+		mode := model.Characteristic{Id: "mode", Name: "mode", Type: model.String, AllowedValues: []interface{}{"eco", "comfort"}}
+		if violations := validation.Characteristic("mode", "eco", mode); len(violations) != 0 {
+			t.Error(violations)
+		}
+		violations := validation.Characteristic("mode", "turbo", mode)
+		if len(violations) != 1 || violations[0].Reason != validation.ReasonAllowedValues {
+			t.Error(violations)
+		}
+	})
+}
+
+func TestUnmarshalValidation(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := metrics.NewMetrics(config.Config{})
+	apiurl := setupWithConfig(ctx, wg, config.Config{
+		ReturnUnknownPathAsNull: true,
+		OutputValidationPolicy:  validation.PolicyFlag,
+		OutputValidationServicePolicies: map[string]string{
+			"null":   validation.PolicyNull,
+			"reject": validation.PolicyReject,
+			"off":    "",
+		},
+	}, m)
+
+	iterations := "urn:infai:ses:characteristic:63769613-47bd-4bb9-9624-083669ee6261"
+	customOrder := "urn:infai:ses:characteristic:aeacd820-8a9e-4a68-a4c6-412537bb8afb"
+
+	protocol := model.Protocol{
+		Id:      "p1",
+		Name:    "p1",
+		Handler: "p1",
+		ProtocolSegments: []model.ProtocolSegment{
+			{Id: "p1.1", Name: "body"},
+		},
+	}
+	service := func(id string) model.Service {
+		return model.Service{
+			Id:          id,
+			LocalId:     id,
+			Name:        id,
+			Interaction: model.EVENT_AND_REQUEST,
+			ProtocolId:  "p1",
+			Outputs: []model.Content{
+				{
+					Id: "content",
+					ContentVariable: model.ContentVariable{
+						Id:   "root",
+						Name: "root",
+						Type: model.Structure,
+						SubContentVariables: []model.ContentVariable{
+							{Id: "iterations", Name: "iterations", Type: model.Integer, CharacteristicId: iterations},
+							{Id: "customOrder", Name: "customOrder", Type: model.Boolean, CharacteristicId: customOrder},
+						},
+					},
+					Serialization:     "json",
+					ProtocolSegmentId: "p1.1",
+				},
+			},
+		}
+	}
+	request := func(serviceId string, path string, body string) messages.UnmarshallingV2Request {
+		return messages.UnmarshallingV2Request{
+			Service:  service(serviceId),
+			Protocol: protocol,
+			Message:  map[string]string{"body": body},
+			Path:     path,
+		}
+	}
+	invalid := `{"iterations":7,"customOrder":"yes"}`
+
+	t.Run("valid", testUnmarshalViolations(apiurl, request("reject", "root.iterations", `{"iterations":2,"customOrder":true}`), http.StatusOK, float64(2), nil))
+
+	t.Run("off", testUnmarshalViolations(apiurl, request("off", "root.iterations", invalid), http.StatusOK, float64(7), nil))
+
+	t.Run("flag", testUnmarshalViolations(apiurl, request("flag", "root.iterations", invalid), http.StatusOK, float64(7), []validation.Violation{
+		{Path: "root.iterations", CharacteristicId: iterations, Reason: validation.ReasonMax},
+	}))
+
+	t.Run("null", testUnmarshalViolations(apiurl, request("null", "root.iterations", invalid), http.StatusOK, nil, []validation.Violation{
+		{Path: "root.iterations", CharacteristicId: iterations, Reason: validation.ReasonMax},
+	}))
+
+	t.Run("null unaffected path", testUnmarshalViolations(apiurl, request("null", "root.iterations", `{"iterations":3,"customOrder":"yes"}`), http.StatusOK, float64(3), nil))
+
+	t.Run("reject structure", testUnmarshalViolations(apiurl, request("reject", "root", invalid), http.StatusUnprocessableEntity, nil, []validation.Violation{
+		{Path: "root.customOrder", Reason: validation.ReasonType},
+		{Path: "root.iterations", CharacteristicId: iterations, Reason: validation.ReasonMax},
+	}))
+
+	t.Run("metrics", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		m.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		result := recorder.Body.String()
+		for _, expected := range []string{
+			`marshaller_unmarshalling_output_violations_total{policy="flag",reason="max",service_id="flag"} 1`,
+			`marshaller_unmarshalling_output_violations_total{policy="null",reason="max",service_id="null"} 1`,
+			`marshaller_unmarshalling_output_violations_total{policy="reject",reason="type",service_id="reject"} 1`,
+		} {
+			if !strings.Contains(result, expected) {
+				t.Error(expected, "\n", result)
+			}
+		}
+	})
+}
+
+func testUnmarshalViolations(apiurl string, request messages.UnmarshallingV2Request, expectedCode int, expectedResult interface{}, expectedViolations []validation.Violation) func(t *testing.T) {
+	return func(t *testing.T) {
+		body := new(bytes.Buffer)
+		err := json.NewEncoder(body).Encode(request)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Post(apiurl+"/v2/unmarshal", "application/json", body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != expectedCode {
+			t.Error(resp.StatusCode)
+			return
+		}
+		violations := []validation.Violation{}
+		if header := resp.Header.Get(api.OutputViolationsHeader); header != "" {
+			err = json.Unmarshal([]byte(header), &violations)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if expectedCode == http.StatusOK {
+			var result interface{}
+			err = json.NewDecoder(resp.Body).Decode(&result)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(result, expectedResult) {
+				t.Error(result, expectedResult)
+			}
+		} else {
+			errResult := messages.ValidationErrorResponse{}
+			err = json.NewDecoder(resp.Body).Decode(&errResult)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(errResult.Violations, violations) {
+				t.Error(errResult.Violations, violations)
+			}
+		}
+		if len(violations) != len(expectedViolations) {
+			t.Error(violations)
+			return
+		}
+		for i, e := range expectedViolations {
+			actual := violations[i]
+			if actual.Path != e.Path || actual.CharacteristicId != e.CharacteristicId || actual.Reason != e.Reason {
+				t.Error(i, actual, e)
+			}
+		}
+	}
+}
+
+func testMarshalViolations(apiurl string, request messages.MarshallingV2Request, expected []validation.Violation) func(t *testing.T) {
+	return func(t *testing.T) {
+		body := new(bytes.Buffer)
+		err := json.NewEncoder(body).Encode(request)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Post(apiurl+"/v2/marshal", "application/json", body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Error(resp.StatusCode)
+			return
+		}
+		result := messages.ValidationErrorResponse{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sort.Slice(result.Violations, func(i, j int) bool {
+			return result.Violations[i].Path < result.Violations[j].Path
+		})
+		if len(result.Violations) != len(expected) {
+			t.Error(result)
+			return
+		}
+		for i, e := range expected {
+			actual := result.Violations[i]
+			if actual.Path != e.Path || actual.CharacteristicId != e.CharacteristicId || actual.Reason != e.Reason || actual.Message == "" {
+				t.Error(i, actual, e)
+			}
+		}
+	}
+}
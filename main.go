@@ -45,11 +45,6 @@ func main() {
 		log.Fatal(err)
 	}
 
-	err = lib.StartCacheInvalidator(ctx, conf)
-	if err != nil {
-		conf.GetLogger().Warn("unable to start cache invalidator", "error", err)
-	}
-
 	go func() {
 		shutdownSignal := make(chan os.Signal, 1)
 		signal.Notify(shutdownSignal, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)